@@ -0,0 +1,186 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy selects what an AsyncSink does with a new entry when its
+// queue is full.
+type OverflowPolicy int
+
+// Defines the available overflow policies.
+const (
+	// OverflowBlock waits for space in the queue.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest discards the new entry.
+	OverflowDropNewest
+	// OverflowDropOldest discards the oldest queued entry to make room.
+	OverflowDropOldest
+)
+
+// AsyncSink wraps a Sink queueing entries in a bounded queue drained by a
+// background goroutine so that slow sinks do not stall logging.  Entries
+// are delivered to the wrapped sink in the order queued.  Flush or Close
+// must be called before the program exits to guarantee delivery.
+type AsyncSink struct {
+	sink    Sink
+	policy  OverflowPolicy
+	queue   chan *Entry
+	done    chan struct{}
+	dropped uint64 // Accessed atomically.
+	queued  uint64 // Accessed atomically.
+
+	closeMu sync.RWMutex // Guards closed and sends to queue.
+	closed  bool
+
+	mu        sync.Mutex // Guards processed.
+	cond      *sync.Cond // Signalled as processed advances.
+	processed uint64     // Entries written or dropped after being queued.
+}
+
+// NewAsyncSink starts a background goroutine delivering entries to the
+// provided sink through a queue holding up to size entries applying the
+// policy when it is full.
+func NewAsyncSink(sink Sink, size int, policy OverflowPolicy) *AsyncSink {
+	if size < 1 {
+		size = 1
+	}
+	s := &AsyncSink{
+		sink:   sink,
+		policy: policy,
+		queue:  make(chan *Entry, size),
+		done:   make(chan struct{}),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	go s.drain()
+	return s
+}
+
+// drain writes queued entries to the wrapped sink until the queue is closed.
+func (s *AsyncSink) drain() {
+	defer close(s.done)
+	for entry := range s.queue {
+		_ = s.sink.WriteEntry(entry)
+		s.advance()
+	}
+}
+
+// advance records that a queued entry has been written or dropped.
+func (s *AsyncSink) advance() {
+	s.mu.Lock()
+	s.processed++
+	s.cond.Broadcast()
+	s.mu.Unlock()
+}
+
+// WriteEntry queues the entry applying the overflow policy if the queue is
+// full.  An error is returned if the AsyncSink has been closed.
+func (s *AsyncSink) WriteEntry(entry *Entry) error {
+	s.closeMu.RLock()
+	defer s.closeMu.RUnlock()
+
+	if s.closed {
+		return errors.New("async sink closed")
+	}
+
+	switch s.policy {
+	case OverflowDropNewest:
+		select {
+		case s.queue <- entry:
+		default:
+			atomic.AddUint64(&s.dropped, 1)
+			return nil
+		}
+	case OverflowDropOldest:
+		for queued := false; !queued; {
+			select {
+			case s.queue <- entry:
+				queued = true
+			default:
+				select {
+				case <-s.queue:
+					atomic.AddUint64(&s.dropped, 1)
+					s.advance()
+				default:
+				}
+			}
+		}
+	default:
+		s.queue <- entry
+	}
+	atomic.AddUint64(&s.queued, 1)
+	return nil
+}
+
+// Dropped returns the number of entries discarded because the queue was
+// full.
+func (s *AsyncSink) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Writer returns the io.Writer of the wrapped sink, or nil if it does not
+// write to one, allowing duplicate writers to be detected.
+func (s *AsyncSink) Writer() io.Writer {
+	if w, ok := s.sink.(writerSink); ok {
+		return w.Writer()
+	}
+	return nil
+}
+
+// Flush waits until every entry queued before the call has been written (or
+// dropped) and then flushes the wrapped sink if it implements Flusher.
+func (s *AsyncSink) Flush() error {
+	target := atomic.LoadUint64(&s.queued)
+
+	s.mu.Lock()
+	for s.processed < target {
+		s.cond.Wait()
+	}
+	s.mu.Unlock()
+
+	if f, ok := s.sink.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Close stops accepting entries, waits for all queued entries to be written
+// and flushes the wrapped sink if it implements Flusher.  The wrapped sink is
+// not closed.
+func (s *AsyncSink) Close() error {
+	s.closeMu.Lock()
+	if s.closed {
+		s.closeMu.Unlock()
+		return errors.New("async sink closed")
+	}
+	s.closed = true
+	close(s.queue)
+	s.closeMu.Unlock()
+
+	<-s.done
+	if f, ok := s.sink.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"bytes"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dancsecs/szTest"
+)
+
+// gatedSink announces each entry it receives and then waits to be released
+// before recording it.
+type gatedSink struct {
+	started chan string
+	release chan struct{}
+
+	mu       sync.Mutex
+	messages []string
+}
+
+func newGatedSink() *gatedSink {
+	return &gatedSink{
+		started: make(chan string, 100),
+		release: make(chan struct{}),
+	}
+}
+
+func (s *gatedSink) WriteEntry(entry *Entry) error {
+	s.started <- entry.Message
+	<-s.release
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, entry.Message)
+	return nil
+}
+
+func (s *gatedSink) got() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return strings.Join(s.messages, ",")
+}
+
+func runOverflowTest(
+	chk *szTest.Chk, policy OverflowPolicy,
+) (string, uint64) {
+	gated := newGatedSink()
+	async := NewAsyncSink(gated, 2, policy)
+
+	chk.NoErr(async.WriteEntry(&Entry{Message: "e1"}))
+	chk.Str(<-gated.started, "e1")
+
+	for i := 2; i <= 5; i++ {
+		chk.NoErr(async.WriteEntry(&Entry{Message: "e" + strconv.Itoa(i)}))
+	}
+	close(gated.release)
+	chk.NoErr(async.Flush())
+	chk.NoErr(async.Close())
+
+	return gated.got(), async.Dropped()
+}
+
+func Test_SzLog_AsyncSinkDropNewest(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	got, dropped := runOverflowTest(chk, OverflowDropNewest)
+	chk.Str(got, "e1,e2,e3")
+	chk.Uint64(dropped, 2)
+}
+
+func Test_SzLog_AsyncSinkDropOldest(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	got, dropped := runOverflowTest(chk, OverflowDropOldest)
+	chk.Str(got, "e1,e4,e5")
+	chk.Uint64(dropped, 2)
+}
+
+func Test_SzLog_AsyncSinkBlock(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	gated := newGatedSink()
+	async := NewAsyncSink(gated, 1, OverflowBlock)
+
+	chk.NoErr(async.WriteEntry(&Entry{Message: "e1"}))
+	chk.Str(<-gated.started, "e1")
+	chk.NoErr(async.WriteEntry(&Entry{Message: "e2"}))
+
+	written := make(chan struct{})
+	go func() {
+		chk.NoErr(async.WriteEntry(&Entry{Message: "e3"}))
+		close(written)
+	}()
+
+	select {
+	case <-written:
+		chk.True(false, "write did not block")
+	default:
+	}
+
+	close(gated.release)
+	<-written
+	chk.NoErr(async.Close())
+
+	chk.Str(gated.got(), "e1,e2,e3")
+	chk.Uint64(async.Dropped(), 0)
+}
+
+func Test_SzLog_AsyncSinkLogger(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	const (
+		writers = 4
+		lines   = 250
+	)
+
+	buf := new(flushBuffer)
+	async := NewAsyncSink(NewTextSink(log.New(buf, "", 0)), 0, OverflowBlock)
+	chk.True(async.Writer() == buf)
+	chk.True(NewAsyncSink(new(testSink), 1, OverflowBlock).Writer() == nil)
+
+	logger := New(ErrorLevel, log.New(new(bytes.Buffer), "", 0))
+	chk.NoErr(logger.AddSink(async))
+	chk.Err(logger.AddWriter(buf, "", 0), "duplicate os.Writer added")
+	chk.NoErr(logger.AddSink(NewAsyncSink(new(testSink), 1, OverflowBlock)))
+
+	var wg sync.WaitGroup
+	wg.Add(writers)
+	for w := 0; w < writers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := 0; i < lines; i++ {
+				logger.Error("line")
+			}
+		}()
+	}
+	wg.Wait()
+
+	chk.NoErr(logger.Flush())
+	chk.Int(strings.Count(buf.String(), "E: line\n"), writers*lines)
+	chk.Int(buf.flushes, 1)
+
+	logger.Error("last")
+	chk.NoErr(async.Close())
+	chk.True(strings.HasSuffix(buf.String(), "E: last\n"))
+	chk.Int(buf.flushes, 2)
+
+	chk.Err(async.WriteEntry(&Entry{}), "async sink closed")
+	chk.Err(async.Close(), "async sink closed")
+}
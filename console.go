@@ -0,0 +1,211 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+)
+
+// EnvNoColor names the environment variable that, when set to any non-empty
+// value, disables automatic coloring as described at https://no-color.org.
+const EnvNoColor = "NO_COLOR"
+
+// ColorMode selects when a ConsoleSink colors its output.
+type ColorMode int
+
+// Defines the ColorModes.
+const (
+	// ColorAuto colors output only when writing to a terminal and the
+	// NO_COLOR environment variable is unset or empty.
+	ColorAuto ColorMode = iota
+	// ColorAlways colors output regardless of where it is written.
+	ColorAlways
+	// ColorNever never colors output.
+	ColorNever
+)
+
+// ANSI escape sequences used to color console output.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiGray    = "\x1b[90m"
+	ansiCyan    = "\x1b[36m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiRed     = "\x1b[31m"
+	ansiBoldRed = "\x1b[1;31m"
+)
+
+// levelColor returns the ANSI escape sequence coloring the level.
+func levelColor(level Level) string {
+	switch level {
+	case PanicLevel, FatalLevel:
+		return ansiBoldRed
+	case ErrorLevel:
+		return ansiRed
+	case WarnLevel:
+		return ansiYellow
+	case InfoLevel:
+		return ansiGreen
+	case DebugLevel:
+		return ansiCyan
+	default:
+		return ansiGray
+	}
+}
+
+// ConsoleOptions defines how a ConsoleSink colors its output.
+type ConsoleOptions struct {
+	// Color selects when output is colored.
+	Color ColorMode
+
+	// WholeLine colors the entire message rather than just its level label.
+	WholeLine bool
+}
+
+// ConsoleSink is a TextSink coloring the level label, or optionally the
+// whole message, of each entry by its severity for display on a terminal.
+// The log.Logger's prefix and flags are not colored.
+type ConsoleSink struct {
+	*TextSink
+	opts    ConsoleOptions
+	colored bool
+}
+
+// NewConsoleSink creates a ConsoleSink writing to the provided log.Logger.
+// With ColorAuto output is colored only if the log.Logger writes to a
+// terminal and NO_COLOR is unset or empty.
+func NewConsoleSink(logger *log.Logger, opts ConsoleOptions) *ConsoleSink {
+	s := &ConsoleSink{TextSink: NewTextSink(logger), opts: opts}
+	switch opts.Color {
+	case ColorAlways:
+		s.colored = true
+	case ColorNever:
+		s.colored = false
+	default:
+		s.colored = os.Getenv(EnvNoColor) == "" && isTerminal(logger.Writer())
+	}
+	if s.colored {
+		s.formatter = colorFormatter{wholeLine: opts.WholeLine}
+	}
+	return s
+}
+
+// Colored returns true if the ConsoleSink colors its output.
+func (s *ConsoleSink) Colored() bool {
+	return s.colored
+}
+
+// withWriter returns a ConsoleSink with a new log.Logger having the same
+// prefix and flags but writing to the provided io.Writer.  Coloring is
+// determined anew for the io.Writer.
+func (s *ConsoleSink) withWriter(w io.Writer) Sink {
+	return NewConsoleSink(
+		log.New(w, s.logger.Prefix(), s.logger.Flags()), s.opts,
+	)
+}
+
+// withLogger returns a ConsoleSink with the same options writing to the
+// provided log.Logger.  Coloring is determined anew for the log.Logger.
+func (s *ConsoleSink) withLogger(logger *log.Logger) Sink {
+	return NewConsoleSink(logger, s.opts)
+}
+
+// isTerminal returns true if the io.Writer is a file open on a character
+// device such as a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorFormatter colors the output of the Formatter of the szLog.Logger
+// writing the entry.  Only the label is colored unless wholeLine is set or
+// the Formatter is not a TextFormatter.
+type colorFormatter struct {
+	wholeLine bool
+}
+
+// Format implements Formatter.
+func (f colorFormatter) Format(entry *Entry) ([]byte, error) {
+	formatter := entry.formatter
+	if formatter == nil {
+		formatter = defaultFormatter
+	}
+	b, err := formatter.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+	color := levelColor(entry.Level)
+	r := make([]byte, 0, len(b)+len(color)+len(ansiReset))
+	r = append(r, color...)
+	if tf, ok := formatter.(*TextFormatter); ok && !f.wholeLine {
+		label := tf.Label(entry.Level)
+		if bytes.HasPrefix(b, []byte(label)) {
+			r = append(r, label...)
+			r = append(r, ansiReset...)
+			return append(r, b[len(label):]...), nil
+		}
+	}
+	r = append(r, b...)
+	return append(r, ansiReset...), nil
+}
+
+// AddConsole wraps the provided io.Writer in a new log.Logger and adds it as
+// a ConsoleSink to the logs output by the selected szLog.Logger.
+func (logger *Logger) AddConsole(
+	newWriter io.Writer, prefix string, flags int, opts ConsoleOptions,
+) error {
+	return logger.AddSink(
+		NewConsoleSink(log.New(newWriter, prefix, flags), opts),
+	)
+}
+
+// AddConsoleAt is as AddConsole but the added log only receives messages
+// permitted by its own level rather than the szLog.Logger's level.
+func (logger *Logger) AddConsoleAt(
+	level Level,
+	newWriter io.Writer, prefix string, flags int, opts ConsoleOptions,
+) error {
+	return logger.AddSinkAt(
+		level, NewConsoleSink(log.New(newWriter, prefix, flags), opts),
+	)
+}
+
+// AddConsole wraps the provided io.Writer in a new log.Logger and adds it as
+// a ConsoleSink to the logs output by the standard szLog.Logger.
+func AddConsole(
+	newWriter io.Writer, prefix string, flags int, opts ConsoleOptions,
+) error {
+	return std.AddConsole(newWriter, prefix, flags, opts)
+}
+
+// AddConsoleAt is as AddConsole but the added log only receives messages
+// permitted by its own level rather than the standard szLog.Logger's level.
+func AddConsoleAt(
+	level Level,
+	newWriter io.Writer, prefix string, flags int, opts ConsoleOptions,
+) error {
+	return std.AddConsoleAt(level, newWriter, prefix, flags, opts)
+}
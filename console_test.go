@@ -0,0 +1,183 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dancsecs/szTest"
+)
+
+func Test_SzLog_ConsoleSinkColors(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	logger := New(TraceLevel, log.New(new(bytes.Buffer), "", 0))
+	logger.SetExitFunc(func(int) {})
+	chk.NoErr(logger.AddConsole(buf, "pre:", 0,
+		ConsoleOptions{Color: ColorAlways},
+	))
+
+	logger.Trace("trace")
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Errorw("line 1\nline 2", "k", "v")
+	logger.Fatal("fatal")
+
+	chk.Str(buf.String(), ""+
+		"pre:\x1b[90mT: \x1b[0mtrace\n"+
+		"pre:\x1b[36mD: \x1b[0mdebug\n"+
+		"pre:\x1b[32mI: \x1b[0minfo\n"+
+		"pre:\x1b[33mW: \x1b[0mwarn\n"+
+		"pre:\x1b[31mE: \x1b[0mline 1\n+  line 2 k=v\n"+
+		"pre:\x1b[1;31mF: \x1b[0mfatal\n",
+	)
+}
+
+func Test_SzLog_ConsoleSinkWholeLine(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	logger := New(InfoLevel, log.New(new(bytes.Buffer), "", 0))
+	chk.NoErr(logger.AddConsoleAt(WarnLevel, buf, "", 0,
+		ConsoleOptions{Color: ColorAlways, WholeLine: true},
+	))
+	chk.NoErr(logger.SetLabel(WarnLevel, "[WARN] "))
+
+	logger.Info("filtered")
+	logger.Warn("warn")
+	logger.Error("line 1\nline 2")
+
+	// Formatters other than a TextFormatter have the whole line colored.
+	logger.SetFormatter(upperFormatter{})
+	logger.Warn("custom")
+
+	chk.Str(buf.String(), ""+
+		"\x1b[33m[WARN] warn\x1b[0m\n"+
+		"\x1b[31mE: line 1\n+  line 2\x1b[0m\n"+
+		"\x1b[33mwarn|CUSTOM\x1b[0m\n",
+	)
+}
+
+func Test_SzLog_ConsoleSinkDetection(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	plain := NewConsoleSink(log.New(buf, "", 0), ConsoleOptions{})
+	chk.False(plain.Colored())
+	chk.False(NewConsoleSink(
+		log.New(buf, "", 0), ConsoleOptions{Color: ColorNever},
+	).Colored())
+
+	f, err := os.Create(filepath.Join(chk.CreateTmpDir(), "console.log"))
+	chk.NoErr(err)
+	defer func() { chk.NoErr(f.Close()) }()
+	chk.False(NewConsoleSink(log.New(f, "", 0), ConsoleOptions{}).Colored())
+
+	// A character device is treated as a terminal unless NO_COLOR is set.
+	dev, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	chk.NoErr(err)
+	defer func() { chk.NoErr(dev.Close()) }()
+
+	t.Setenv(EnvNoColor, "")
+	tty := NewConsoleSink(log.New(dev, "", 0), ConsoleOptions{})
+	chk.True(tty.Colored())
+
+	t.Setenv(EnvNoColor, "1")
+	chk.False(NewConsoleSink(log.New(dev, "", 0), ConsoleOptions{}).Colored())
+	chk.True(NewConsoleSink(
+		log.New(dev, "", 0), ConsoleOptions{Color: ColorAlways},
+	).Colored())
+
+	// Replacing the writer determines coloring anew.
+	logger := New(InfoLevel, log.New(new(bytes.Buffer), "", 0))
+	chk.NoErr(logger.AddSink(plain))
+	t.Setenv(EnvNoColor, "")
+	chk.NoErr(logger.ReplaceWriter(buf, dev))
+	sinks := logger.Sinks()
+	replaced, ok := sinks[len(sinks)-1].(*ConsoleSink)
+	chk.True(ok)
+	chk.True(replaced.Colored())
+}
+
+func Test_SzLog_ConsoleSinkLogger(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	console := log.New(buf, "", 0)
+	logger := New(InfoLevel, log.New(new(bytes.Buffer), "", 0))
+	chk.NoErr(logger.AddSink(
+		NewConsoleSink(console, ConsoleOptions{Color: ColorAlways}),
+	))
+	chk.Err(logger.AddLogger(console), "duplicate logger added")
+	chk.NoErr(logger.RemoveLogger(console))
+	chk.Err(logger.RemoveLogger(console), "logger not found")
+
+	// Redirecting the log package keeps a ConsoleSink on log.Default().
+	origWriter, origFlags := log.Writer(), log.Flags()
+	log.SetOutput(buf)
+	log.SetFlags(0)
+	defer func() {
+		log.SetOutput(origWriter)
+		log.SetFlags(origFlags)
+	}()
+
+	chk.NoErr(logger.AddSink(
+		NewConsoleSink(log.Default(), ConsoleOptions{Color: ColorAlways}),
+	))
+	restore := logger.RedirectStdLog(WarnLevel)
+	log.Print("redirected")
+	restore()
+	logger.Info("restored")
+
+	chk.Str(buf.String(), ""+
+		"\x1b[33mW: \x1b[0mredirected\n"+
+		"\x1b[32mI: \x1b[0mrestored\n",
+	)
+	_, ok := logger.Sinks()[1].(*ConsoleSink)
+	chk.True(ok)
+}
+
+func Test_SzLog_Default_AddConsole(t *testing.T) {
+	chk := szTest.CaptureLog(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	chk.NoErr(AddConsole(buf, "", 0, ConsoleOptions{Color: ColorAlways}))
+	defer func() { chk.NoErr(RemoveWriter(buf)) }()
+	trace := new(bytes.Buffer)
+	chk.NoErr(AddConsoleAt(TraceLevel, trace, "", 0, ConsoleOptions{}))
+	defer func() { chk.NoErr(RemoveWriter(trace)) }()
+
+	Error("error")
+	Trace("trace")
+
+	chk.Str(buf.String(), "\x1b[31mE: \x1b[0merror\n")
+	chk.Str(trace.String(), "E: error\nT: trace\n")
+	chk.Log("E: error\n")
+}
@@ -0,0 +1,193 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"context"
+)
+
+// Keys used for the fields added by ContextWithRequestID and
+// ContextWithTraceID.
+const (
+	RequestIDKey = "request_id"
+	TraceIDKey   = "trace_id"
+)
+
+// contextKey identifies values stored in a context.Context by this package.
+type contextKey int
+
+const (
+	loggerKey contextKey = iota
+	fieldsKey
+)
+
+// NewContext returns a copy of the context carrying the szLog.Logger.
+func NewContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// FromContext returns the szLog.Logger carried by the context or the
+// standard szLog.Logger if there is none.
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey).(*Logger); ok && logger != nil {
+			return logger
+		}
+	}
+	return std
+}
+
+// ContextWith returns a copy of the context carrying the alternating keys
+// and values (or Fields) in addition to any it already carries.  They are
+// added to every message logged with one of the Ctx variants.
+func ContextWith(ctx context.Context, keysAndValues ...any) context.Context {
+	existing := ContextFields(ctx)
+	fields := fieldsFrom(keysAndValues)
+	combined := make([]Field, 0, len(existing)+len(fields))
+	combined = append(append(combined, existing...), fields...)
+	return context.WithValue(ctx, fieldsKey, combined)
+}
+
+// ContextWithRequestID returns a copy of the context carrying the request
+// id as a "request_id" field.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return ContextWith(ctx, RequestIDKey, id)
+}
+
+// ContextWithTraceID returns a copy of the context carrying the trace id as
+// a "trace_id" field.
+func ContextWithTraceID(ctx context.Context, id string) context.Context {
+	return ContextWith(ctx, TraceIDKey, id)
+}
+
+// ContextFields returns the fields carried by the context.
+func ContextFields(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey).([]Field)
+	return fields
+}
+
+// contextFields returns the fields carried by the context followed by the
+// alternating keys and values (or Fields).
+func contextFields(ctx context.Context, keysAndValues []any) []Field {
+	existing := ContextFields(ctx)
+	if len(existing) == 0 {
+		return fieldsFrom(keysAndValues)
+	}
+	n := len(existing)
+	return append(existing[:n:n], fieldsFrom(keysAndValues)...)
+}
+
+// TraceCtx writes a message with the context's fields followed by the
+// alternating keys and values (or Fields) to the selected szLog.Logger if
+// trace level messages are enabled.
+func (logger *Logger) TraceCtx(
+	ctx context.Context, msg string, keysAndValues ...any,
+) {
+	if logger.IsTrace() {
+		logger.output(TraceLevel, msg, contextFields(ctx, keysAndValues))
+	}
+}
+
+// DebugCtx writes a message with the context's fields followed by the
+// alternating keys and values (or Fields) to the selected szLog.Logger if
+// debug level messages are enabled.
+func (logger *Logger) DebugCtx(
+	ctx context.Context, msg string, keysAndValues ...any,
+) {
+	if logger.IsDebug() {
+		logger.output(DebugLevel, msg, contextFields(ctx, keysAndValues))
+	}
+}
+
+// InfoCtx writes a message with the context's fields followed by the
+// alternating keys and values (or Fields) to the selected szLog.Logger if
+// information level messages are enabled.
+func (logger *Logger) InfoCtx(
+	ctx context.Context, msg string, keysAndValues ...any,
+) {
+	if logger.IsInfo() {
+		logger.output(InfoLevel, msg, contextFields(ctx, keysAndValues))
+	}
+}
+
+// WarnCtx writes a message with the context's fields followed by the
+// alternating keys and values (or Fields) to the selected szLog.Logger if
+// warning level messages are enabled.
+func (logger *Logger) WarnCtx(
+	ctx context.Context, msg string, keysAndValues ...any,
+) {
+	if logger.IsWarn() {
+		logger.output(WarnLevel, msg, contextFields(ctx, keysAndValues))
+	}
+}
+
+// ErrorCtx writes a message with the context's fields followed by the
+// alternating keys and values (or Fields) to the selected szLog.Logger.
+// Error level is always enabled.
+func (logger *Logger) ErrorCtx(
+	ctx context.Context, msg string, keysAndValues ...any,
+) {
+	logger.output(ErrorLevel, msg, contextFields(ctx, keysAndValues))
+}
+
+// TraceCtx writes a message with the context's fields followed by the
+// alternating keys and values (or Fields) to the context's szLog.Logger, or
+// the standard szLog.Logger, if trace level messages are enabled.
+func TraceCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	if logger := FromContext(ctx); logger.IsTrace() {
+		logger.output(TraceLevel, msg, contextFields(ctx, keysAndValues))
+	}
+}
+
+// DebugCtx writes a message with the context's fields followed by the
+// alternating keys and values (or Fields) to the context's szLog.Logger, or
+// the standard szLog.Logger, if debug level messages are enabled.
+func DebugCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	if logger := FromContext(ctx); logger.IsDebug() {
+		logger.output(DebugLevel, msg, contextFields(ctx, keysAndValues))
+	}
+}
+
+// InfoCtx writes a message with the context's fields followed by the
+// alternating keys and values (or Fields) to the context's szLog.Logger, or
+// the standard szLog.Logger, if information level messages are enabled.
+func InfoCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	if logger := FromContext(ctx); logger.IsInfo() {
+		logger.output(InfoLevel, msg, contextFields(ctx, keysAndValues))
+	}
+}
+
+// WarnCtx writes a message with the context's fields followed by the
+// alternating keys and values (or Fields) to the context's szLog.Logger, or
+// the standard szLog.Logger, if warning level messages are enabled.
+func WarnCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	if logger := FromContext(ctx); logger.IsWarn() {
+		logger.output(WarnLevel, msg, contextFields(ctx, keysAndValues))
+	}
+}
+
+// ErrorCtx writes a message with the context's fields followed by the
+// alternating keys and values (or Fields) to the context's szLog.Logger, or
+// the standard szLog.Logger.  Error level is always enabled.
+func ErrorCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	FromContext(ctx).output(ErrorLevel, msg, contextFields(ctx, keysAndValues))
+}
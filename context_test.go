@@ -0,0 +1,141 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"strconv"
+	"testing"
+
+	"github.com/dancsecs/szTest"
+)
+
+func Test_SzLog_FromContext(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	logger := New(InfoLevel, log.New(io.Discard, "", 0))
+	ctx := NewContext(context.Background(), logger)
+
+	chk.True(FromContext(ctx) == logger)
+	chk.True(FromContext(context.Background()) == Default())
+	chk.True(FromContext(NewContext(ctx, nil)) == Default())
+	chk.True(FromContext(nil) == Default()) //nolint:staticcheck // Ok.
+}
+
+func Test_SzLog_ContextFields(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	base := ContextWithRequestID(context.Background(), "r1")
+	ctx := ContextWith(ContextWithTraceID(base, "t1"), Int("n", 1), "odd")
+	other := ContextWith(base, "k", "v")
+
+	fields := ContextFields(ctx)
+	chk.Int(len(fields), 4)
+	chk.Str(fields[0].String(), "request_id=r1")
+	chk.Str(fields[1].String(), "trace_id=t1")
+	chk.Str(fields[2].String(), "n=1")
+	chk.Str(fields[3].String(), "!BADKEY=odd")
+
+	// Deriving another context does not disturb its siblings.
+	chk.Int(len(ContextFields(base)), 1)
+	chk.Int(len(ContextFields(other)), 2)
+	chk.Str(ContextFields(other)[1].String(), "k=v")
+	chk.Int(len(ContextFields(nil)), 0) //nolint:staticcheck // Ok.
+}
+
+func Test_SzLog_CtxVariants(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	rec := new(recordSink)
+	logger := New(DebugLevel, log.New(buf, "", 0))
+	chk.NoErr(logger.AddSink(rec))
+
+	ctx := ContextWithRequestID(context.Background(), "r1")
+	ctx = NewContext(ctx, logger.With("svc", "api"))
+
+	want := []int{}
+	want = append(want, nextLine())
+	logger.TraceCtx(ctx, "not logged")
+	want = append(want, nextLine())
+	logger.DebugCtx(ctx, "debug", "n", 1)
+	want = append(want, nextLine())
+	logger.InfoCtx(context.Background(), "info")
+	want = append(want, nextLine())
+	logger.WarnCtx(ctx, "warn")
+	want = append(want, nextLine())
+	logger.ErrorCtx(ctx, "error")
+	want = append(want, nextLine())
+	TraceCtx(ctx, "not logged")
+	want = append(want, nextLine())
+	DebugCtx(ctx, "debug")
+	want = append(want, nextLine())
+	InfoCtx(ctx, "info", "n", 2)
+	want = append(want, nextLine())
+	WarnCtx(ctx, "warn")
+	want = append(want, nextLine())
+	ErrorCtx(ctx, "error")
+
+	chk.Str(buf.String(), ""+
+		"D: debug request_id=r1 n=1\n"+
+		"I: info\n"+
+		"W: warn request_id=r1\n"+
+		"E: error request_id=r1\n"+
+		"D: debug svc=api request_id=r1\n"+
+		"I: info svc=api request_id=r1 n=2\n"+
+		"W: warn svc=api request_id=r1\n"+
+		"E: error svc=api request_id=r1\n",
+	)
+
+	want = append(want[1:5], want[6:]...)
+	chk.Int(len(rec.entries), len(want))
+	for i, e := range rec.entries {
+		chk.Int(e.Caller.Line, want[i], strconv.Itoa(i))
+	}
+}
+
+func Test_SzLog_Default_CtxVariants(t *testing.T) {
+	chk := szTest.CaptureLog(t)
+	defer chk.Release()
+
+	SetLevel(TraceLevel)
+	defer SetLevel(ErrorLevel)
+
+	ctx := ContextWithTraceID(context.Background(), "t1")
+
+	TraceCtx(ctx, "trace")
+	DebugCtx(ctx, "debug")
+	InfoCtx(ctx, "info")
+	WarnCtx(ctx, "warn")
+	ErrorCtx(ctx, "error", "n", 1)
+
+	chk.Log("" +
+		"T: trace trace_id=t1\n" +
+		"D: debug trace_id=t1\n" +
+		"I: info trace_id=t1\n" +
+		"W: warn trace_id=t1\n" +
+		"E: error trace_id=t1 n=1\n" +
+		"")
+}
@@ -0,0 +1,165 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Defines the environment variables read by ConfigureFromEnv.
+const (
+	EnvLevel  = "SZLOG_LEVEL"
+	EnvFormat = "SZLOG_FORMAT"
+	EnvFile   = "SZLOG_FILE"
+)
+
+// Defines the output formats selectable with SZLOG_FORMAT.
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// logFilePerm is the permission used when creating a log file.
+const logFilePerm = 0o600
+
+// ConfigureFromEnv configures the selected szLog.Logger from the following
+// environment variables, all of which are optional with an empty variable
+// treated as unset:
+//
+//	SZLOG_LEVEL   the logging level as accepted by ParseLevel.
+//	SZLOG_FORMAT  either "text" (the default) or "json".
+//	SZLOG_FILE    a file to append log messages to.
+//
+// When SZLOG_FILE is set a sink in the selected format writing to a LogFile
+// is added unless a sink already writes to the file.  Otherwise a
+// SZLOG_FORMAT of "json" replaces every sink writing through a log.Logger
+// with a JSONSink writing to the log.Logger's current io.Writer without its
+// prefix or flags.  Calling it again makes no further change.  All variables
+// are validated before any change is made and an error naming the offending
+// variable is returned should any be invalid.  An opened file remains open
+// for the life of the program but may be reopened with Reopen.
+func (logger *Logger) ConfigureFromEnv() error {
+	levelText := os.Getenv(EnvLevel)
+	hasLevel := strings.TrimSpace(levelText) != ""
+	format := strings.ToLower(strings.TrimSpace(os.Getenv(EnvFormat)))
+	fPath := os.Getenv(EnvFile)
+
+	level, err := ParseLevel(levelText)
+	if hasLevel && err != nil {
+		return fmt.Errorf("%s: %w", EnvLevel, err)
+	}
+	if format == "" {
+		format = formatText
+	}
+	if format != formatText && format != formatJSON {
+		return fmt.Errorf("%s: invalid format: %q", EnvFormat, format)
+	}
+
+	if fPath != "" && !logger.writesToFile(fPath) {
+		f, err := NewLogFile(fPath)
+		if err != nil {
+			return fmt.Errorf("%s: %w", EnvFile, err)
+		}
+		if format == formatJSON {
+			err = logger.AddJSONWriter(f)
+		} else {
+			err = logger.AddWriter(f, "", log.LstdFlags)
+		}
+		if err != nil {
+			logger.Close(f)
+			return fmt.Errorf("%s: %w", EnvFile, err)
+		}
+	} else if format == formatJSON {
+		logger.formatSinksJSON()
+	}
+
+	if hasLevel {
+		logger.SetLevel(level)
+	}
+	return nil
+}
+
+// pathWriter is implemented by io.Writers, such as LogFile and RotatingFile,
+// writing to a named file.
+type pathWriter interface {
+	Path() string
+}
+
+// writesToFile returns true if any sink writes to the file at path.
+func (logger *Logger) writesToFile(path string) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	for _, s := range logger.core().sinks() {
+		ws, ok := s.Sink.(writerSink)
+		if !ok {
+			continue
+		}
+		pw, ok := ws.Writer().(pathWriter)
+		if !ok {
+			continue
+		}
+		if p, err := filepath.Abs(pw.Path()); err == nil && p == abs {
+			return true
+		}
+	}
+	return false
+}
+
+// formatSinksJSON replaces every sink writing through a log.Logger with a
+// JSONSink writing to the log.Logger's current io.Writer.  Per sink levels
+// are kept.
+func (logger *Logger) formatSinksJSON() {
+	c := logger.core()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sinks := c.sinks()
+	newSinks := make([]levelSink, len(sinks))
+	copy(newSinks, sinks)
+	for i, s := range newSinks {
+		if l, ok := s.Sink.(loggerSink); ok {
+			newSinks[i].Sink = NewJSONSink(loggerWriter{logger: l.Logger()})
+		}
+	}
+	c.logs.Store(newSinks)
+}
+
+// loggerWriter is an io.Writer writing to whatever io.Writer the log.Logger
+// is currently writing to.
+type loggerWriter struct {
+	logger *log.Logger
+}
+
+// Write writes p to the log.Logger's current io.Writer.
+func (w loggerWriter) Write(p []byte) (int, error) {
+	return w.logger.Writer().Write(p)
+}
+
+// ConfigureFromEnv configures the standard szLog.Logger from the SZLOG_LEVEL,
+// SZLOG_FORMAT and SZLOG_FILE environment variables.  See
+// Logger.ConfigureFromEnv for details.
+func ConfigureFromEnv() error {
+	return std.ConfigureFromEnv()
+}
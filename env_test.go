@@ -0,0 +1,191 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/dancsecs/szTest"
+)
+
+var stdTime = regexp.MustCompile(`(?m)^\d{4}/\d\d/\d\d \d\d:\d\d:\d\d `)
+
+func Test_SzLog_ConfigureFromEnvNone(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	t.Setenv(EnvLevel, "")
+	t.Setenv(EnvFormat, "")
+	t.Setenv(EnvFile, "")
+
+	logger := New(WarnLevel, log.Default())
+	chk.NoErr(logger.ConfigureFromEnv())
+	chk.Int(int(logger.Level()), int(WarnLevel))
+	chk.Int(len(logger.Sinks()), 1)
+
+	chk.NoErr(ConfigureFromEnv())
+}
+
+func Test_SzLog_ConfigureFromEnvTextFile(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	fPath := filepath.Join(chk.CreateTmpDir(), "app.log")
+	t.Setenv(EnvLevel, "debug")
+	t.Setenv(EnvFormat, "")
+	t.Setenv(EnvFile, fPath)
+
+	logger := New(ErrorLevel, log.New(new(bytes.Buffer), "", 0))
+	chk.NoErr(logger.ConfigureFromEnv())
+	chk.Int(int(logger.Level()), int(DebugLevel))
+	chk.Int(len(logger.Sinks()), 2)
+
+	// The file is not opened again.
+	chk.NoErr(logger.ConfigureFromEnv())
+	chk.Int(len(logger.Sinks()), 2)
+
+	logger.Debug("debug message")
+	f, _ := logger.Sinks()[1].(*TextSink).Writer().(*LogFile)
+	chk.NoErr(logger.RemoveWriter(f))
+	chk.NoErr(f.Close())
+
+	b, err := os.ReadFile(fPath)
+	chk.NoErr(err)
+	chk.Str(stdTime.ReplaceAllString(string(b), ""), "D: debug message\n")
+}
+
+func Test_SzLog_ConfigureFromEnvJSONFile(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	fPath := filepath.Join(chk.CreateTmpDir(), "app.log")
+	t.Setenv(EnvLevel, "info")
+	t.Setenv(EnvFormat, "JSON")
+	t.Setenv(EnvFile, fPath)
+
+	logger := New(ErrorLevel, log.New(new(bytes.Buffer), "", 0))
+	chk.NoErr(logger.ConfigureFromEnv())
+
+	logger.Infow("info message", "k", 1)
+	f, _ := logger.Sinks()[1].(*JSONSink).Writer().(*LogFile)
+	chk.NoErr(f.Close())
+
+	b, err := os.ReadFile(fPath)
+	chk.NoErr(err)
+	chk.Str(
+		normJSON(string(b)),
+		`{"time":"T","level":"info","msg":"info message","fields":{"k":1}}`+
+			"\n",
+	)
+}
+
+func Test_SzLog_ConfigureFromEnvJSONDefault(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	origWriter := log.Writer()
+	origFlags := log.Flags()
+	log.SetOutput(new(bytes.Buffer))
+	log.SetFlags(log.LstdFlags)
+	defer func() {
+		log.SetOutput(origWriter)
+		log.SetFlags(origFlags)
+	}()
+
+	t.Setenv(EnvLevel, "")
+	t.Setenv(EnvFormat, "json")
+	t.Setenv(EnvFile, "")
+
+	logger := New(ErrorLevel, log.Default())
+	chk.NoErr(logger.ConfigureFromEnv())
+	chk.Int(len(logger.Sinks()), 1)
+	chk.NoErr(logger.ConfigureFromEnv())
+	chk.Int(len(logger.Sinks()), 1)
+
+	// The JSONSink follows the log.Logger's output.
+	log.SetOutput(buf)
+	logger.Error("error message")
+
+	chk.Str(
+		normJSON(buf.String()),
+		`{"time":"T","level":"error","msg":"error message"}`+"\n",
+	)
+}
+
+func Test_SzLog_ConfigureFromEnvJSONText(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	t.Setenv(EnvLevel, "info")
+	t.Setenv(EnvFormat, "json")
+	t.Setenv(EnvFile, "")
+
+	buf1 := new(bytes.Buffer)
+	buf2 := new(bytes.Buffer)
+	logger := New(ErrorLevel, log.New(buf1, "one:", log.LstdFlags))
+	chk.NoErr(logger.AddSinkAt(WarnLevel, NewJSONSink(buf2)))
+	chk.NoErr(logger.ConfigureFromEnv())
+	chk.NoErr(logger.ConfigureFromEnv())
+
+	logger.Info("info message")
+	logger.Warn("warn message")
+
+	chk.Str(
+		normJSON(buf1.String()),
+		`{"time":"T","level":"info","msg":"info message"}`+"\n"+
+			`{"time":"T","level":"warn","msg":"warn message"}`+"\n",
+	)
+	chk.Str(
+		normJSON(buf2.String()),
+		`{"time":"T","level":"warn","msg":"warn message"}`+"\n",
+	)
+}
+
+func Test_SzLog_ConfigureFromEnvErrors(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	logger := New(WarnLevel, log.Default())
+
+	t.Setenv(EnvLevel, "loud")
+	t.Setenv(EnvFormat, "")
+	t.Setenv(EnvFile, "")
+	chk.Err(logger.ConfigureFromEnv(), EnvLevel+`: invalid level: "loud"`)
+
+	t.Setenv(EnvLevel, "info")
+	t.Setenv(EnvFormat, "xml")
+	chk.Err(logger.ConfigureFromEnv(), EnvFormat+`: invalid format: "xml"`)
+
+	fPath := filepath.Join(chk.CreateTmpDir(), "missing", "app.log")
+	t.Setenv(EnvFormat, "text")
+	t.Setenv(EnvFile, fPath)
+	chk.Err(
+		logger.ConfigureFromEnv(),
+		EnvFile+": open "+fPath+": no such file or directory",
+	)
+
+	chk.Int(int(logger.Level()), int(WarnLevel))
+	chk.Int(len(logger.Sinks()), 1)
+}
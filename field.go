@@ -0,0 +1,155 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// badKey is used as the key for a trailing value that has no key.
+const badKey = "!BADKEY"
+
+// Field represents a key/value pair carried alongside a log message.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// String returns a Field holding a string value.
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int returns a Field holding an int value.
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int64 returns a Field holding an int64 value.
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Uint64 returns a Field holding a uint64 value.
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Float64 returns a Field holding a float64 value.
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Bool returns a Field holding a bool value.
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Duration returns a Field holding a time.Duration value.
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Time returns a Field holding a time.Time value.
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err returns a Field holding an error under the key "error".
+func Err(err error) Field {
+	return Field{Key: "error", Value: err}
+}
+
+// Any returns a Field holding an arbitrary value.
+func Any(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// String renders the field in its key=value text form.
+func (f Field) String() string {
+	return f.Key + "=" + quoteValue(f.ValueString())
+}
+
+// ValueString returns the field's value as an unquoted string.
+func (f Field) ValueString() string {
+	switch v := f.Value.(type) {
+	case nil:
+		return "<nil>"
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case error:
+		return v.Error()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// fieldsFrom converts alternating keys and values into fields.  Arguments
+// that are already Fields are used directly.  A key without a value is
+// recorded as the value of a "!BADKEY" field.
+func fieldsFrom(keysAndValues []any) []Field {
+	if len(keysAndValues) == 0 {
+		return nil
+	}
+	fields := make([]Field, 0, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); i++ {
+		switch arg := keysAndValues[i].(type) {
+		case Field:
+			fields = append(fields, arg)
+		default:
+			if i+1 == len(keysAndValues) {
+				fields = append(fields, Field{Key: badKey, Value: arg})
+			} else {
+				fields = append(
+					fields, Field{Key: fmt.Sprint(arg), Value: keysAndValues[i+1]},
+				)
+				i++
+			}
+		}
+	}
+	return fields
+}
+
+// quoteValue quotes the value if it is empty or contains spaces, quotes,
+// equal signs or non printable characters.
+func quoteValue(value string) string {
+	if value == "" {
+		return `""`
+	}
+	if strings.IndexFunc(value, func(r rune) bool {
+		return r == '=' || r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r)
+	}) >= 0 {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// appendFields appends the text form of each field preceded by a space.
+func appendFields(b *strings.Builder, fields []Field) {
+	for _, f := range fields {
+		b.WriteByte(' ')
+		b.WriteString(f.String())
+	}
+}
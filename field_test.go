@@ -0,0 +1,64 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dancsecs/szTest"
+)
+
+func Test_SzLog_FieldConstructors(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	tm := time.Date(2023, 11, 19, 10, 20, 30, 0, time.UTC)
+
+	chk.Str(String("s", "abc").String(), "s=abc")
+	chk.Str(String("s", "").String(), `s=""`)
+	chk.Str(String("s", "a b").String(), `s="a b"`)
+	chk.Str(String("s", "a=b").String(), `s="a=b"`)
+	chk.Str(String("s", "a\nb").String(), `s="a\nb"`)
+	chk.Str(Int("i", -3).String(), "i=-3")
+	chk.Str(Int64("i64", 64).String(), "i64=64")
+	chk.Str(Uint64("u64", 64).String(), "u64=64")
+	chk.Str(Float64("f", 1.5).String(), "f=1.5")
+	chk.Str(Bool("b", true).String(), "b=true")
+	chk.Str(Duration("d", 1500*time.Millisecond).String(), "d=1.5s")
+	chk.Str(Time("t", tm).String(), "t=2023-11-19T10:20:30Z")
+	chk.Str(Err(errors.New("bad thing")).String(), `error="bad thing"`)
+	chk.Str(Any("a", nil).String(), "a=<nil>")
+	chk.Str(Any("a", []int{1, 2}).String(), `a="[1 2]"`)
+}
+
+func Test_SzLog_FieldsFrom(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	chk.Int(len(fieldsFrom(nil)), 0)
+
+	fields := fieldsFrom([]any{"k1", 1, Bool("k2", false), 3, "v3", "k4"})
+	chk.Int(len(fields), 4)
+	chk.Str(fields[0].String(), "k1=1")
+	chk.Str(fields[1].String(), "k2=false")
+	chk.Str(fields[2].String(), "3=v3")
+	chk.Str(fields[3].String(), "!BADKEY=k4")
+}
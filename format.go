@@ -0,0 +1,218 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Formatter renders an entry as the bytes written for it.  Implementations
+// must be safe for concurrent use.
+type Formatter interface {
+	Format(entry *Entry) ([]byte, error)
+}
+
+// TextFormatter renders entries in the labeled text form:
+//
+//	E: first line key=value
+//	+  second line
+//
+// The label identifies the level, each additional line of a multi-line
+// message is preceded by the continuation label and any fields follow the
+// message as key=value pairs.  No trailing newline is added.  It is the
+// default Formatter used by text sinks.
+//
+// The zero value uses the default labels.  Others may be substituted with
+// WithLabel and WithContinuation which return modified copies leaving the
+// original, which may be in use, unchanged.
+type TextFormatter struct {
+	labels       map[Level]string // Replacements for the default labels.
+	continuation string
+	hasContinue  bool // The continuation label replaces the default.
+}
+
+// Label returns the label identifying the level.
+func (f *TextFormatter) Label(level Level) string {
+	if label, ok := f.labels[level]; ok {
+		return label
+	}
+	return levelLabel(level)
+}
+
+// Continuation returns the label preceding each additional line of a
+// multi-line message.
+func (f *TextFormatter) Continuation() string {
+	if f.hasContinue {
+		return f.continuation
+	}
+	return continueLabel
+}
+
+// WithLabel returns a copy of the TextFormatter using the label for the
+// level.
+func (f *TextFormatter) WithLabel(level Level, label string) *TextFormatter {
+	labels := make(map[Level]string, len(f.labels)+1)
+	for l, v := range f.labels {
+		labels[l] = v
+	}
+	labels[level] = label
+	return &TextFormatter{
+		labels:       labels,
+		continuation: f.continuation,
+		hasContinue:  f.hasContinue,
+	}
+}
+
+// WithContinuation returns a copy of the TextFormatter using the label for
+// each additional line of a multi-line message.
+func (f *TextFormatter) WithContinuation(label string) *TextFormatter {
+	return &TextFormatter{
+		labels:       f.labels,
+		continuation: label,
+		hasContinue:  true,
+	}
+}
+
+// Format implements Formatter.
+func (f *TextFormatter) Format(entry *Entry) ([]byte, error) {
+	var r strings.Builder
+	r.WriteString(f.Label(entry.Level))
+	for i, l := range strings.Split(entry.Message, "\n") {
+		if i > 0 {
+			r.WriteString("\n" + f.Continuation())
+		}
+		r.WriteString(l)
+	}
+	appendFields(&r, entry.Fields)
+	return []byte(r.String()), nil
+}
+
+// JSONFormatter renders entries as single line JSON objects terminated by a
+// newline as described by JSONSink.
+type JSONFormatter struct{}
+
+// Format implements Formatter.
+func (f *JSONFormatter) Format(entry *Entry) ([]byte, error) {
+	return appendJSON(nil, entry), nil
+}
+
+// defaultFormatter is used by text sinks when no other Formatter is set.
+//
+//nolint:goCheckNoGlobals // ok
+var defaultFormatter Formatter = new(TextFormatter)
+
+// formatterValue wraps a Formatter so that differing implementations may be
+// held by an atomic.Value.
+type formatterValue struct {
+	Formatter
+}
+
+// SetFormatter sets the Formatter used by text sinks of the selected
+// szLog.Logger that have no Formatter of their own, returning the previous
+// Formatter.  A nil Formatter restores the default TextFormatter.
+func (logger *Logger) SetFormatter(formatter Formatter) Formatter {
+	c := logger.core()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if formatter == nil {
+		formatter = defaultFormatter
+	}
+	last, _ := c.format.Swap(formatterValue{formatter}).(formatterValue)
+	if last.Formatter == nil {
+		return defaultFormatter
+	}
+	return last.Formatter
+}
+
+// Formatter returns the Formatter used by text sinks of the selected
+// szLog.Logger that have no Formatter of their own.
+func (logger *Logger) Formatter() Formatter {
+	if f, ok := logger.core().format.Load().(formatterValue); ok {
+		return f.Formatter
+	}
+	return defaultFormatter
+}
+
+// SetLabel replaces the label identifying the level in the TextFormatter of
+// the selected szLog.Logger.  An error is returned if the level is not valid
+// or if another Formatter has been set.
+func (logger *Logger) SetLabel(level Level, label string) error {
+	if level > TraceLevel && level < FatalLevel {
+		return fmt.Errorf("invalid level: %s", level)
+	}
+	return logger.updateTextFormatter(func(f *TextFormatter) *TextFormatter {
+		return f.WithLabel(level, label)
+	})
+}
+
+// SetContinuation replaces the label preceding each additional line of a
+// multi-line message in the TextFormatter of the selected szLog.Logger.  An
+// error is returned if another Formatter has been set.
+func (logger *Logger) SetContinuation(label string) error {
+	return logger.updateTextFormatter(func(f *TextFormatter) *TextFormatter {
+		return f.WithContinuation(label)
+	})
+}
+
+// updateTextFormatter replaces the logger's TextFormatter with the modified
+// copy returned by update.
+func (logger *Logger) updateTextFormatter(
+	update func(f *TextFormatter) *TextFormatter,
+) error {
+	c := logger.core()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f, ok := logger.Formatter().(*TextFormatter)
+	if !ok {
+		return errors.New("formatter is not a TextFormatter")
+	}
+	c.format.Store(formatterValue{update(f)})
+	return nil
+}
+
+// SetFormatter sets the Formatter used by text sinks of the standard
+// szLog.Logger that have no Formatter of their own, returning the previous
+// Formatter.  A nil Formatter restores the default TextFormatter.
+func SetFormatter(formatter Formatter) Formatter {
+	return std.SetFormatter(formatter)
+}
+
+// GetFormatter returns the Formatter used by text sinks of the standard
+// szLog.Logger that have no Formatter of their own.
+func GetFormatter() Formatter {
+	return std.Formatter()
+}
+
+// SetLabel replaces the label identifying the level in the TextFormatter of
+// the standard szLog.Logger.  An error is returned if the level is not valid
+// or if another Formatter has been set.
+func SetLabel(level Level, label string) error {
+	return std.SetLabel(level, label)
+}
+
+// SetContinuation replaces the label preceding each additional line of a
+// multi-line message in the TextFormatter of the standard szLog.Logger.  An
+// error is returned if another Formatter has been set.
+func SetContinuation(label string) error {
+	return std.SetContinuation(label)
+}
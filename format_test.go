@@ -0,0 +1,227 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dancsecs/szTest"
+)
+
+// upperFormatter renders the level name and upper cased message.
+type upperFormatter struct{}
+
+func (upperFormatter) Format(entry *Entry) ([]byte, error) {
+	return []byte(entry.Level.String() + "|" + strings.ToUpper(entry.Message)),
+		nil
+}
+
+// failFormatter always fails.
+type failFormatter struct{}
+
+func (failFormatter) Format(_ *Entry) ([]byte, error) {
+	return nil, errors.New("cannot format")
+}
+
+func Test_SzLog_TextAndJSONFormatters(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	entry := &Entry{
+		Time:    time.Date(2023, 11, 19, 10, 20, 30, 0, time.UTC),
+		Level:   WarnLevel,
+		Message: "line 1\nline 2",
+		Fields:  []Field{Int("n", 1), String("s", "a b")},
+	}
+
+	b, err := new(TextFormatter).Format(entry)
+	chk.NoErr(err)
+	chk.Str(string(b), "W: line 1\n+  line 2 n=1 s=\"a b\"")
+
+	b, err = new(JSONFormatter).Format(entry)
+	chk.NoErr(err)
+	chk.Str(string(b), ""+
+		`{"time":"2023-11-19T10:20:30Z","level":"warn",`+
+		`"msg":"line 1\nline 2","fields":{"n":1,"s":"a b"}}`+"\n",
+	)
+}
+
+func Test_SzLog_SetFormatter(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	own := new(bytes.Buffer)
+	logger := New(InfoLevel, log.New(buf, "", 0))
+	chk.NoErr(logger.AddSink(
+		NewFormattedTextSink(log.New(own, "own:", 0), new(JSONFormatter)),
+	))
+
+	chk.True(logger.Formatter() == defaultFormatter)
+	chk.True(logger.With("k", "v").SetFormatter(upperFormatter{}) ==
+		defaultFormatter)
+	chk.True(logger.Formatter() == upperFormatter{})
+
+	logger.Info("custom")
+	chk.True(logger.SetFormatter(nil) == upperFormatter{})
+	logger.Info("default")
+
+	// The formatter is kept when the writer is replaced.
+	replaced := new(bytes.Buffer)
+	chk.NoErr(logger.ReplaceWriter(own, replaced))
+	logger.Warn("replaced")
+
+	chk.Str(buf.String(), ""+
+		"info|CUSTOM\n"+
+		"I: default\n"+
+		"W: replaced\n",
+	)
+	chk.Str(normJSON(own.String()), ""+
+		`own:{"time":"T","level":"info","msg":"custom"}`+"\n"+
+		`own:{"time":"T","level":"info","msg":"default"}`+"\n",
+	)
+	chk.Str(normJSON(replaced.String()), ""+
+		`own:{"time":"T","level":"warn","msg":"replaced"}`+"\n",
+	)
+}
+
+func Test_SzLog_FormatterError(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	sink := NewFormattedTextSink(log.New(buf, "", 0), failFormatter{})
+	chk.Err(
+		sink.WriteEntry(&Entry{Level: ErrorLevel, Message: "msg"}),
+		"cannot format",
+	)
+	chk.Str(buf.String(), "")
+
+	// Entries not written by a szLog.Logger use the default formatter.
+	chk.NoErr(NewTextSink(log.New(buf, "", 0)).WriteEntry(
+		&Entry{Level: ErrorLevel, Message: "msg"},
+	))
+	chk.Str(buf.String(), "E: msg\n")
+}
+
+func Test_SzLog_Default_SetFormatter(t *testing.T) {
+	chk := szTest.CaptureLog(t)
+	defer chk.Release()
+
+	last := SetFormatter(upperFormatter{})
+	defer SetFormatter(last)
+
+	chk.True(GetFormatter() == upperFormatter{})
+	Error("custom")
+	SetFormatter(last)
+	Error("default")
+
+	chk.Log("" +
+		"error|CUSTOM\n" +
+		"E: default\n" +
+		"")
+}
+
+func Test_SzLog_TextFormatterLabels(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	base := new(TextFormatter)
+	f := base.WithLabel(DebugLevel, "[DEBUG] ").WithContinuation("")
+	g := f.WithLabel(InfoLevel, "[INFO]  ").WithContinuation("        ")
+
+	chk.Str(base.Label(DebugLevel), "D: ")
+	chk.Str(base.Continuation(), "+  ")
+	chk.Str(f.Label(DebugLevel), "[DEBUG] ")
+	chk.Str(f.Label(InfoLevel), "I: ")
+	chk.Str(f.Continuation(), "")
+	chk.Str(g.Label(DebugLevel), "[DEBUG] ")
+	chk.Str(g.Label(InfoLevel), "[INFO]  ")
+	chk.Str(g.Continuation(), "        ")
+
+	b, err := g.Format(&Entry{Level: InfoLevel, Message: "a\nb"})
+	chk.NoErr(err)
+	chk.Str(string(b), "[INFO]  a\n        b")
+}
+
+func Test_SzLog_SetLabel(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	logger := New(TraceLevel, log.New(buf, "", 0))
+	logger.SetExitFunc(func(int) {})
+
+	chk.NoErr(logger.SetLabel(TraceLevel, "TRACE "))
+	chk.NoErr(logger.With("k", "v").SetLabel(ErrorLevel, "ERROR "))
+	chk.NoErr(logger.SetLabel(FatalLevel, "FATAL "))
+	chk.NoErr(logger.SetContinuation("    | "))
+	chk.Err(logger.SetLabel(Level(5), "bad"), "invalid level: level(5)")
+
+	logger.Trace("trace")
+	logger.Debug("debug")
+	logger.Error("line 1\nline 2")
+	logger.Fatal("fatal")
+
+	chk.NoErr(logger.SetLabel(ErrorLevel, "E: "))
+	chk.NoErr(logger.SetContinuation("+  "))
+	logger.Error("line 1\nline 2")
+
+	logger.SetFormatter(upperFormatter{})
+	chk.Err(
+		logger.SetLabel(ErrorLevel, "ERROR "),
+		"formatter is not a TextFormatter",
+	)
+	chk.Err(logger.SetContinuation(""), "formatter is not a TextFormatter")
+
+	chk.Str(buf.String(), ""+
+		"TRACE trace\n"+
+		"D: debug\n"+
+		"ERROR line 1\n"+
+		"    | line 2\n"+
+		"FATAL fatal\n"+
+		"E: line 1\n"+
+		"+  line 2\n",
+	)
+}
+
+func Test_SzLog_Default_SetLabel(t *testing.T) {
+	chk := szTest.CaptureLog(t)
+	defer chk.Release()
+
+	defer SetFormatter(nil)
+
+	chk.NoErr(SetLabel(ErrorLevel, "[ERROR] "))
+	chk.NoErr(SetContinuation("[ERROR] "))
+	Error("line 1\nline 2")
+	SetFormatter(nil)
+	Error("line 1\nline 2")
+
+	chk.Log("" +
+		"[ERROR] line 1\n" +
+		"[ERROR] line 2\n" +
+		"E: line 1\n" +
+		"+  line 2\n" +
+		"")
+}
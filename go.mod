@@ -1,5 +1,5 @@
 module github.com/dancsecs/szLog
 
-go 1.18
+go 1.21
 
 require github.com/dancsecs/szTest v0.0.0-20231113191917-44f657b7238d
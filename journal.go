@@ -0,0 +1,219 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// defaultJournalSocket is the socket on which journald accepts native
+// protocol messages.
+const defaultJournalSocket = "/run/systemd/journal/socket"
+
+// journalFields are the names of the fields set by a JournalSink which are
+// not to be replaced by an entry's fields.
+//
+//nolint:goCheckNoGlobals // ok
+var journalFields = map[string]bool{
+	"PRIORITY":          true,
+	"MESSAGE":           true,
+	"SYSLOG_IDENTIFIER": true,
+	"CODE_FILE":         true,
+	"CODE_LINE":         true,
+	"CODE_FUNC":         true,
+}
+
+// JournalOptions defines where a JournalSink sends entries.
+type JournalOptions struct {
+	// Socket is the journald native protocol socket.  It defaults to
+	// "/run/systemd/journal/socket".
+	Socket string
+
+	// Tag is sent as SYSLOG_IDENTIFIER.  It defaults to the program's name.
+	Tag string
+
+	// Fallback receives entries, prefixed for sd-daemon, when the socket
+	// is missing or a send fails.  It defaults to os.Stderr.
+	Fallback io.Writer
+}
+
+// JournalSink is a Sink sending entries to systemd's journal using its native
+// protocol.  Each entry is sent with its PRIORITY, MESSAGE, CODE_FILE,
+// CODE_LINE, CODE_FUNC and SYSLOG_IDENTIFIER along with its fields under
+// upper cased names, those clashing with the former being prefixed by "F".
+// Should the socket be missing, or a send fail, entries are written as text
+// to the fallback with each line prefixed by its priority (for example "<3>")
+// as understood by sd-daemon.  Entries too large for a single datagram cannot
+// be sent to the journal.
+type JournalSink struct {
+	opts JournalOptions
+	addr *net.UnixAddr
+
+	mu   sync.Mutex
+	conn *net.UnixConn // Nil if the fallback is in use.
+}
+
+// NewJournalSink creates a JournalSink sending to the journal's socket or,
+// if it does not exist, writing to the fallback.
+func NewJournalSink(opts JournalOptions) (*JournalSink, error) {
+	if opts.Socket == "" {
+		opts.Socket = defaultJournalSocket
+	}
+	if opts.Tag == "" {
+		opts.Tag = filepath.Base(os.Args[0])
+	}
+	if opts.Fallback == nil {
+		opts.Fallback = os.Stderr
+	}
+	s := &JournalSink{
+		opts: opts,
+		addr: &net.UnixAddr{Name: opts.Socket, Net: "unixgram"},
+	}
+	if _, err := os.Stat(opts.Socket); err != nil {
+		return s, nil //nolint:nilerr // Fallback in use.
+	}
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	s.conn = conn
+	return s, nil
+}
+
+// Fallback returns true if entries are written to the fallback rather than
+// sent to the journal.
+func (s *JournalSink) Fallback() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conn == nil
+}
+
+// WriteEntry sends the entry to the journal, or writes it to the fallback.
+func (s *JournalSink) WriteEntry(entry *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn != nil {
+		msg := s.format(entry)
+		if _, err := s.conn.WriteToUnix(msg, s.addr); err == nil {
+			return nil
+		}
+	}
+	return s.writeFallback(entry)
+}
+
+// Close closes the socket used to send to the journal.  Subsequent entries
+// are written to the fallback.
+func (s *JournalSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// format returns the entry in the journal's native protocol.
+func (s *JournalSink) format(entry *Entry) []byte {
+	var b bytes.Buffer
+	appendJournalField(&b, "PRIORITY", strconv.Itoa(syslogSeverity(entry.Level)))
+	appendJournalField(&b, "MESSAGE", entry.Message)
+	appendJournalField(&b, "SYSLOG_IDENTIFIER", s.opts.Tag)
+	if entry.Caller.File != "" {
+		appendJournalField(&b, "CODE_FILE", entry.Caller.File)
+		appendJournalField(&b, "CODE_LINE", strconv.Itoa(entry.Caller.Line))
+		if fn := runtime.FuncForPC(entry.Caller.PC); fn != nil {
+			appendJournalField(&b, "CODE_FUNC", fn.Name())
+		}
+	}
+	for _, f := range entry.Fields {
+		appendJournalField(&b, journalName(f.Key), f.ValueString())
+	}
+	return b.Bytes()
+}
+
+// writeFallback writes the entry as text with each line prefixed by its
+// priority.  It must be called with mu held.
+func (s *JournalSink) writeFallback(entry *Entry) error {
+	formatter := entry.formatter
+	if formatter == nil {
+		formatter = defaultFormatter
+	}
+	text, err := formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	prefix := "<" + strconv.Itoa(syslogSeverity(entry.Level)) + ">"
+	lines := strings.Split(strings.TrimSuffix(string(text), "\n"), "\n")
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(prefix + l + "\n")
+	}
+	_, err = io.WriteString(s.opts.Fallback, b.String())
+	return err
+}
+
+// appendJournalField appends the field in the native protocol.  Values
+// containing newlines are sent with their length as a little endian 64 bit
+// integer rather than terminated by a newline.
+func appendJournalField(b *bytes.Buffer, name, value string) {
+	if !strings.Contains(value, "\n") {
+		b.WriteString(name + "=" + value + "\n")
+		return
+	}
+	b.WriteString(name + "\n")
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(value)))
+	b.Write(size[:])
+	b.WriteString(value + "\n")
+}
+
+// journalName returns the key as a valid journal field name: at most 64
+// upper case letters, digits and underscores starting with a letter.
+// Invalid characters are replaced by underscores and keys not starting with
+// a letter, or naming a field set by the JournalSink, are prefixed by "F".
+func journalName(key string) string {
+	const maxLen = 64
+	name := []byte(strings.ToUpper(key))
+	for i, c := range name {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			name[i] = '_'
+		}
+	}
+	if len(name) == 0 || name[0] < 'A' || name[0] > 'Z' ||
+		journalFields[string(name)] {
+		name = append([]byte{'F'}, name...)
+	}
+	if len(name) > maxLen {
+		name = name[:maxLen]
+	}
+	return string(name)
+}
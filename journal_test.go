@@ -0,0 +1,173 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"log"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dancsecs/szTest"
+)
+
+// parseJournal decodes a native protocol datagram into name=value lines
+// with newlines in values shown as \n.
+func parseJournal(chk *szTest.Chk, msg []byte) []string {
+	var fields []string
+	for len(msg) > 0 {
+		i := bytes.IndexByte(msg, '\n')
+		chk.True(i >= 0)
+		line := string(msg[:i])
+		msg = msg[i+1:]
+		if strings.Contains(line, "=") {
+			fields = append(fields, line)
+			continue
+		}
+		size := int(binary.LittleEndian.Uint64(msg[:8]))
+		value := string(msg[8 : 8+size])
+		chk.Str(string(msg[8+size]), "\n")
+		msg = msg[8+size+1:]
+		fields = append(fields,
+			line+"="+strings.ReplaceAll(value, "\n", `\n`),
+		)
+	}
+	return fields
+}
+
+func Test_SzLog_JournalSink(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	path := filepath.Join(chk.CreateTmpDir(), "socket")
+	server, err := net.ListenPacket("unixgram", path)
+	chk.NoErr(err)
+
+	fallback := new(bytes.Buffer)
+	sink, err := NewJournalSink(JournalOptions{
+		Socket: path, Tag: "app", Fallback: fallback,
+	})
+	chk.NoErr(err)
+	chk.False(sink.Fallback())
+
+	logger := New(InfoLevel, log.New(io.Discard, "", 0))
+	chk.NoErr(logger.AddSink(sink))
+
+	line := nextLine()
+	logger.Warnw("line 1\nline 2",
+		"user-id", 7, "_x", "y", "9", true, "priority", "high",
+	)
+	logger.Debug("filtered")
+
+	buf := make([]byte, 4096)
+	n, _, err := server.ReadFrom(buf)
+	chk.NoErr(err)
+	fields := parseJournal(chk, buf[:n])
+	chk.Int(len(fields), 10)
+	chk.Str(fields[0], "PRIORITY=4")
+	chk.Str(fields[1], `MESSAGE=line 1\nline 2`)
+	chk.Str(fields[2], "SYSLOG_IDENTIFIER=app")
+	chk.True(strings.HasPrefix(fields[3], "CODE_FILE=/"))
+	chk.True(strings.HasSuffix(fields[3], "/journal_test.go"))
+	chk.Str(fields[4], "CODE_LINE="+strconv.Itoa(line))
+	chk.Str(fields[5],
+		"CODE_FUNC=github.com/dancsecs/szLog.Test_SzLog_JournalSink",
+	)
+	chk.Str(fields[6], "USER_ID=7")
+	chk.Str(fields[7], "F_X=y")
+	chk.Str(fields[8], "F9=true")
+	chk.Str(fields[9], "FPRIORITY=high")
+
+	// Failed sends are written to the fallback.
+	chk.NoErr(server.Close())
+	logger.Error("lost")
+	chk.NoErr(sink.Close())
+	chk.True(sink.Fallback())
+	logger.Info("closed")
+	chk.NoErr(sink.Close())
+
+	chk.Str(fallback.String(), ""+
+		"<3>E: lost\n"+
+		"<6>I: closed\n",
+	)
+}
+
+func Test_SzLog_JournalSinkFallback(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	fallback := new(bytes.Buffer)
+	sink, err := NewJournalSink(JournalOptions{
+		Socket:   filepath.Join(chk.CreateTmpDir(), "missing"),
+		Fallback: fallback,
+	})
+	chk.NoErr(err)
+	chk.True(sink.Fallback())
+
+	logger := New(TraceLevel, log.New(io.Discard, "", 0))
+	chk.NoErr(logger.AddSink(sink))
+	logger.SetExitFunc(func(int) {})
+
+	logger.Trace("trace")
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warnw("multi\nline", "k", "v")
+	logger.Error("error")
+	logger.Fatal("fatal")
+	chk.Panic(func() { logger.Panic("panic") }, "panic")
+
+	chk.NoErr(logger.SetLabel(ErrorLevel, "[ERROR] "))
+	logger.Error("labeled")
+	logger.SetFormatter(failFormatter{})
+	logger.Error("unformatted")
+
+	chk.Str(fallback.String(), ""+
+		"<7>T: trace\n"+
+		"<7>D: debug\n"+
+		"<6>I: info\n"+
+		"<4>W: multi\n"+
+		"<4>+  line k=v\n"+
+		"<3>E: error\n"+
+		"<2>F: fatal\n"+
+		"<1>P: panic\n"+
+		"<3>[ERROR] labeled\n",
+	)
+	chk.Err(
+		sink.WriteEntry(&Entry{formatter: failFormatter{}}),
+		"cannot format",
+	)
+}
+
+func Test_SzLog_JournalName(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	chk.Str(journalName("requestId"), "REQUESTID")
+	chk.Str(journalName("a.b-c d"), "A_B_C_D")
+	chk.Str(journalName(""), "F")
+	chk.Str(journalName("message"), "FMESSAGE")
+	chk.Str(journalName("code-line"), "FCODE_LINE")
+	chk.Str(journalName("messages"), "MESSAGES")
+	chk.Str(journalName(strings.Repeat("x", 70)), strings.Repeat("X", 64))
+}
@@ -0,0 +1,176 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Level stores the current level of permitted logging.
+type Level uint32
+
+// Defines the various logging levels.
+const (
+	ErrorLevel Level = iota
+	WarnLevel
+	InfoLevel
+	DebugLevel
+	TraceLevel
+)
+
+// Defines the severities above ErrorLevel used for fatal and panic messages.
+// Like ErrorLevel they are always enabled.  They are numbered from the top of
+// the range so as not to disturb the logging levels above.
+const (
+	PanicLevel Level = math.MaxUint32 - iota
+	FatalLevel
+)
+
+const (
+	traceLabel    = "T: "
+	debugLabel    = "D: "
+	infoLabel     = "I: "
+	warnLabel     = "W: "
+	errorLabel    = "E: "
+	fatalLabel    = "F: "
+	panicLabel    = "P: "
+	continueLabel = "+  "
+)
+
+// permittedBy returns true if messages at the level are permitted by a log
+// filtering at the threshold level.
+func (l Level) permittedBy(threshold Level) bool {
+	return l <= threshold || l >= FatalLevel
+}
+
+// levelLabel returns the text label identifying the level.
+func levelLabel(level Level) string {
+	switch level {
+	case PanicLevel:
+		return panicLabel
+	case FatalLevel:
+		return fatalLabel
+	case ErrorLevel:
+		return errorLabel
+	case WarnLevel:
+		return warnLabel
+	case InfoLevel:
+		return infoLabel
+	case DebugLevel:
+		return debugLabel
+	default:
+		return traceLabel
+	}
+}
+
+// String returns the lower case name of the level.
+func (l Level) String() string {
+	switch l {
+	case PanicLevel:
+		return "panic"
+	case FatalLevel:
+		return "fatal"
+	case ErrorLevel:
+		return "error"
+	case WarnLevel:
+		return "warn"
+	case InfoLevel:
+		return "info"
+	case DebugLevel:
+		return "debug"
+	case TraceLevel:
+		return "trace"
+	default:
+		return fmt.Sprintf("level(%d)", uint32(l))
+	}
+}
+
+// ParseLevel returns the logging level named by the text.  Names are case
+// insensitive and include "warning" as an alias for "warn".  The decimal
+// value of a logging level is also accepted for compatibility with stored
+// configurations.  FatalLevel and PanicLevel are not logging levels so are
+// rejected.
+func ParseLevel(text string) (Level, error) {
+	name := strings.ToLower(strings.TrimSpace(text))
+	switch name {
+	case "trace":
+		return TraceLevel, nil
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	}
+	if n, err := strconv.ParseUint(name, 10, 32); err == nil {
+		if Level(n) <= TraceLevel {
+			return Level(n), nil
+		}
+	}
+	return ErrorLevel, fmt.Errorf("invalid level: %q", text)
+}
+
+// MarshalText implements encoding.TextMarshaler.  An error is returned for
+// levels, such as FatalLevel, that are not logging levels as they could not
+// be unmarshaled.
+func (l Level) MarshalText() ([]byte, error) {
+	if l > TraceLevel {
+		return nil, fmt.Errorf("invalid level: %s", l)
+	}
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseLevel.
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler accepting a level's name as a
+// string or, as stored by earlier configurations, its decimal value as a
+// number.  A null leaves the level unchanged.
+func (l *Level) UnmarshalJSON(data []byte) error {
+	text := string(bytes.TrimSpace(data))
+	if text == "null" {
+		return nil
+	}
+	if strings.HasPrefix(text, `"`) {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	}
+	return l.UnmarshalText([]byte(text))
+}
+
+// Set implements flag.Value using ParseLevel allowing a level to be bound
+// with flag.Var.
+func (l *Level) Set(text string) error {
+	return l.UnmarshalText([]byte(text))
+}
@@ -0,0 +1,150 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/dancsecs/szTest"
+)
+
+func Test_SzLog_LevelString(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	chk.Str(TraceLevel.String(), "trace")
+	chk.Str(DebugLevel.String(), "debug")
+	chk.Str(InfoLevel.String(), "info")
+	chk.Str(WarnLevel.String(), "warn")
+	chk.Str(ErrorLevel.String(), "error")
+	chk.Str(FatalLevel.String(), "fatal")
+	chk.Str(PanicLevel.String(), "panic")
+	chk.Str(Level(99).String(), "level(99)")
+}
+
+func Test_SzLog_ParseLevel(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	for text, want := range map[string]Level{
+		"trace":   TraceLevel,
+		"DEBUG":   DebugLevel,
+		" Info ":  InfoLevel,
+		"warn":    WarnLevel,
+		"warning": WarnLevel,
+		"error":   ErrorLevel,
+		"0":       ErrorLevel,
+		"3":       DebugLevel,
+		"4":       TraceLevel,
+	} {
+		level, err := ParseLevel(text)
+		chk.NoErr(err, text)
+		chk.Int(int(level), int(want), text)
+	}
+
+	for _, text := range []string{"", "verbose", "fatal", "panic", "5", "-1"} {
+		_, err := ParseLevel(text)
+		chk.Err(err, "invalid level: \""+text+"\"")
+	}
+}
+
+func Test_SzLog_LevelText(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	type config struct {
+		Level Level `json:"level"`
+	}
+
+	b, err := json.Marshal(config{Level: DebugLevel})
+	chk.NoErr(err)
+	chk.Str(string(b), `{"level":"debug"}`)
+
+	// Every level marshaled is unmarshaled unchanged.
+	for l := ErrorLevel; l <= TraceLevel; l++ {
+		text, err := l.MarshalText()
+		chk.NoErr(err)
+		var got Level
+		chk.NoErr(got.UnmarshalText(text))
+		chk.Int(int(got), int(l))
+	}
+	for _, l := range []Level{TraceLevel + 1, FatalLevel, PanicLevel} {
+		_, err = l.MarshalText()
+		chk.Err(err, "invalid level: "+l.String())
+	}
+
+	var cfg config
+	chk.NoErr(json.Unmarshal([]byte(`{"level":"warning"}`), &cfg))
+	chk.Int(int(cfg.Level), int(WarnLevel))
+
+	chk.Err(
+		json.Unmarshal([]byte(`{"level":"loud"}`), &cfg),
+		"invalid level: \"loud\"",
+	)
+	chk.Int(int(cfg.Level), int(WarnLevel))
+}
+
+func Test_SzLog_LevelJSONNumber(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	var cfg struct{ Level Level }
+	chk.NoErr(json.Unmarshal([]byte(`{"Level":3}`), &cfg))
+	chk.Int(int(cfg.Level), int(DebugLevel))
+
+	chk.NoErr(json.Unmarshal([]byte(`{"Level":null}`), &cfg))
+	chk.Int(int(cfg.Level), int(DebugLevel))
+
+	chk.NoErr(json.Unmarshal([]byte(`{"Level":"3"}`), &cfg))
+	chk.Int(int(cfg.Level), int(DebugLevel))
+
+	chk.Err(
+		json.Unmarshal([]byte(`{"Level":7}`), &cfg),
+		"invalid level: \"7\"",
+	)
+	chk.Err(
+		json.Unmarshal([]byte(`{"Level":true}`), &cfg),
+		"invalid level: \"true\"",
+	)
+	chk.Int(int(cfg.Level), int(DebugLevel))
+}
+
+func Test_SzLog_LevelFlag(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	level := InfoLevel
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.Var(&level, "log-level", "logging level")
+
+	chk.NoErr(flags.Parse([]string{"--log-level=trace"}))
+	chk.Int(int(level), int(TraceLevel))
+	chk.Str(flags.Lookup("log-level").Value.String(), "trace")
+	chk.Str(flags.Lookup("log-level").DefValue, "info")
+
+	chk.Err(
+		flags.Parse([]string{"--log-level=loud"}),
+		`invalid value "loud" for flag -log-level: invalid level: "loud"`,
+	)
+}
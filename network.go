@@ -0,0 +1,331 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Framing selects how a NetworkSink delimits entries on the wire.
+type Framing int
+
+// Defines the available framings.
+const (
+	// FramingNewline terminates each entry with a newline.
+	FramingNewline Framing = iota
+	// FramingOctetCount precedes each entry with its length in bytes and a
+	// space as defined by RFC 6587.
+	FramingOctetCount
+)
+
+// Defaults applied to zero NetworkOptions.
+const (
+	defaultNetworkBuffer  = 1000
+	defaultMinBackoff     = 100 * time.Millisecond
+	defaultMaxBackoff     = 30 * time.Second
+	defaultNetworkTimeout = 5 * time.Second
+)
+
+// NetworkOptions defines how a NetworkSink formats, frames and delivers
+// entries.
+type NetworkOptions struct {
+	// Formatter renders each entry.  It defaults to a JSONFormatter.
+	Formatter Formatter
+
+	// Framing delimits entries on the wire.
+	Framing Framing
+
+	// BufferSize is the number of entries held awaiting delivery.  When
+	// full the oldest is dropped.  It defaults to 1000.
+	BufferSize int
+
+	// MinBackoff and MaxBackoff bound the delay between reconnection
+	// attempts which doubles after each failure.  They default to 100ms and
+	// 30s respectively.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// Timeout limits each connection attempt and write.  It defaults to 5s.
+	Timeout time.Duration
+}
+
+// NetworkSink is a Sink forwarding entries to a remote aggregator over TCP
+// or UDP.  Entries are queued in a bounded buffer and delivered by a
+// background goroutine so logging never waits on the network.  Lost
+// connections are reestablished with exponential backoff, entries being
+// buffered meanwhile.  Close must be called to release the connection.
+type NetworkSink struct {
+	network string
+	address string
+	opts    NetworkOptions
+	stop    chan struct{}
+	done    chan struct{}
+	sent    uint64 // Accessed atomically.
+	dropped uint64 // Accessed atomically.
+
+	mu       sync.Mutex
+	cond     *sync.Cond // Signalled as delivery progresses.
+	queue    [][]byte
+	inFlight []byte // Removed from the queue and awaiting delivery.
+	failed   bool   // The last connection attempt or write failed.
+	closed   bool
+	stopped  bool // The background goroutine has exited.
+}
+
+// NewNetworkSink returns a NetworkSink delivering entries to the address on
+// the network which must be one of "tcp", "tcp4", "tcp6", "udp", "udp4" or
+// "udp6".  The connection is made in the background.
+func NewNetworkSink(
+	network, address string, opts NetworkOptions,
+) (*NetworkSink, error) {
+	if !strings.HasPrefix(network, "tcp") && !strings.HasPrefix(network, "udp") {
+		return nil, errors.New("unsupported network: " + network)
+	}
+	if opts.Formatter == nil {
+		opts.Formatter = new(JSONFormatter)
+	}
+	if opts.BufferSize < 1 {
+		opts.BufferSize = defaultNetworkBuffer
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = defaultMinBackoff
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = defaultMaxBackoff
+		if opts.MaxBackoff < opts.MinBackoff {
+			opts.MaxBackoff = opts.MinBackoff
+		}
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultNetworkTimeout
+	}
+	s := &NetworkSink{
+		network: network,
+		address: address,
+		opts:    opts,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	go s.run()
+	return s, nil
+}
+
+// Sent returns the number of entries delivered.
+func (s *NetworkSink) Sent() uint64 {
+	return atomic.LoadUint64(&s.sent)
+}
+
+// Dropped returns the number of entries discarded because the buffer was
+// full or the sink was closed before they could be delivered.
+func (s *NetworkSink) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// WriteEntry formats and queues the entry for delivery dropping the oldest
+// queued entry if the buffer is full.  It never waits on the network.
+func (s *NetworkSink) WriteEntry(entry *Entry) error {
+	b, err := s.opts.Formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	msg := s.frame(b)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errors.New("network sink closed")
+	}
+	if len(s.queue) >= s.opts.BufferSize {
+		s.queue[0] = nil
+		s.queue = s.queue[1:]
+		atomic.AddUint64(&s.dropped, 1)
+	}
+	s.queue = append(s.queue, msg)
+	s.cond.Broadcast()
+	return nil
+}
+
+// frame returns the formatted entry delimited as selected by the options.
+func (s *NetworkSink) frame(b []byte) []byte {
+	b = bytes.TrimRight(b, "\n")
+	if s.opts.Framing == FramingOctetCount {
+		return append([]byte(strconv.Itoa(len(b))+" "), b...)
+	}
+	msg := make([]byte, 0, len(b)+1)
+	return append(append(msg, b...), '\n')
+}
+
+// Flush waits until all queued entries have been delivered returning an
+// error, rather than waiting, if the remote side cannot be reached.
+func (s *NetworkSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for s.pending() && !s.failed && !s.stopped {
+		s.cond.Wait()
+	}
+	if s.pending() {
+		return errors.New("network sink not connected")
+	}
+	return nil
+}
+
+// pending returns true if entries await delivery.  It must be called with mu
+// held.
+func (s *NetworkSink) pending() bool {
+	return len(s.queue) > 0 || s.inFlight != nil
+}
+
+// Close delivers any queued entries if connected, dropping them otherwise,
+// and closes the connection.
+func (s *NetworkSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.stop)
+	s.cond.Broadcast()
+	s.mu.Unlock()
+
+	<-s.done
+	return nil
+}
+
+// next waits for an entry to deliver returning false once the sink is closed
+// with nothing left to deliver.  An entry whose delivery failed is returned
+// again.
+func (s *NetworkSink) next() ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for s.inFlight == nil && len(s.queue) == 0 && !s.closed {
+		s.cond.Wait()
+	}
+	if s.inFlight == nil && len(s.queue) > 0 {
+		s.inFlight = s.queue[0]
+		s.queue[0] = nil
+		s.queue = s.queue[1:]
+	}
+	return s.inFlight, s.inFlight != nil
+}
+
+// setFailed records the outcome of a connection attempt or write, counting
+// a delivered entry.
+func (s *NetworkSink) setFailed(failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failed = failed
+	if !failed {
+		s.inFlight = nil
+		atomic.AddUint64(&s.sent, 1)
+	}
+	s.cond.Broadcast()
+}
+
+// run delivers queued entries until the sink is closed.
+func (s *NetworkSink) run() {
+	var conn net.Conn
+	defer func() {
+		if conn != nil {
+			_ = conn.Close()
+		}
+		s.mu.Lock()
+		n := len(s.queue)
+		if s.inFlight != nil {
+			n++
+		}
+		atomic.AddUint64(&s.dropped, uint64(n))
+		s.queue, s.inFlight = nil, nil
+		s.stopped = true
+		s.cond.Broadcast()
+		s.mu.Unlock()
+		close(s.done)
+	}()
+
+	backoff := s.opts.MinBackoff
+	for {
+		msg, ok := s.next()
+		if !ok {
+			return
+		}
+		var err error
+		if conn == nil {
+			if s.isClosed() {
+				return
+			}
+			conn, err = net.DialTimeout(s.network, s.address, s.opts.Timeout)
+		}
+		if err == nil {
+			_ = conn.SetWriteDeadline(time.Now().Add(s.opts.Timeout))
+			_, err = conn.Write(msg)
+		}
+		if err == nil {
+			s.setFailed(false)
+			backoff = s.opts.MinBackoff
+			continue
+		}
+
+		// Reconnect after waiting for the backoff which doubles with each
+		// consecutive failure.
+		if conn != nil {
+			_ = conn.Close()
+			conn = nil
+		}
+		s.setFailed(true)
+		if !s.wait(backoff) {
+			return
+		}
+		backoff *= 2
+		if backoff > s.opts.MaxBackoff {
+			backoff = s.opts.MaxBackoff
+		}
+	}
+}
+
+// isClosed returns true if Close has been called.
+func (s *NetworkSink) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// wait pauses for the duration returning false if the sink is closed
+// meanwhile.
+func (s *NetworkSink) wait(d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-s.stop:
+		return false
+	}
+}
@@ -0,0 +1,191 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"bufio"
+	"io"
+	"log"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dancsecs/szTest"
+)
+
+// freeAddress returns a local TCP address nothing is listening on.
+func freeAddress(chk *szTest.Chk) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	chk.NoErr(err)
+	addr := l.Addr().String()
+	chk.NoErr(l.Close())
+	return addr
+}
+
+func Test_SzLog_NetworkSinkTCP(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	server, err := net.Listen("tcp", "127.0.0.1:0")
+	chk.NoErr(err)
+	defer func() { chk.NoErr(server.Close()) }()
+
+	sink, err := NewNetworkSink("tcp", server.Addr().String(), NetworkOptions{
+		Formatter: new(TextFormatter),
+	})
+	chk.NoErr(err)
+
+	logger := New(InfoLevel, log.New(io.Discard, "", 0))
+	chk.NoErr(logger.AddSink(sink))
+	logger.Info("first")
+	logger.Warnw("second", "k", "v")
+	chk.NoErr(logger.Flush())
+	chk.Uint64(sink.Sent(), 2)
+	chk.Uint64(sink.Dropped(), 0)
+
+	conn, err := server.Accept()
+	chk.NoErr(err)
+	defer func() { chk.NoErr(conn.Close()) }()
+	r := bufio.NewReader(conn)
+	for _, want := range []string{"I: first\n", "W: second k=v\n"} {
+		line, err := r.ReadString('\n')
+		chk.NoErr(err)
+		chk.Str(line, want)
+	}
+
+	chk.NoErr(sink.Close())
+	chk.NoErr(sink.Close())
+	chk.Err(sink.WriteEntry(&Entry{}), "network sink closed")
+}
+
+func Test_SzLog_NetworkSinkOctetCount(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	server, err := net.Listen("tcp", "127.0.0.1:0")
+	chk.NoErr(err)
+	defer func() { chk.NoErr(server.Close()) }()
+
+	sink, err := NewNetworkSink("tcp4", server.Addr().String(), NetworkOptions{
+		Framing: FramingOctetCount,
+	})
+	chk.NoErr(err)
+	defer func() { chk.NoErr(sink.Close()) }()
+
+	chk.NoErr(sink.WriteEntry(&Entry{
+		Time:    time.Date(2023, 11, 19, 10, 20, 30, 0, time.UTC),
+		Level:   ErrorLevel,
+		Message: "line 1\nline 2",
+	}))
+
+	conn, err := server.Accept()
+	chk.NoErr(err)
+	defer func() { chk.NoErr(conn.Close()) }()
+	chk.Str(readFrame(chk, bufio.NewReader(conn)), ""+
+		`{"time":"2023-11-19T10:20:30Z","level":"error",`+
+		`"msg":"line 1\nline 2"}`,
+	)
+}
+
+func Test_SzLog_NetworkSinkUDP(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	chk.NoErr(err)
+	defer func() { chk.NoErr(server.Close()) }()
+
+	sink, err := NewNetworkSink("udp", server.LocalAddr().String(),
+		NetworkOptions{Formatter: new(TextFormatter)},
+	)
+	chk.NoErr(err)
+	defer func() { chk.NoErr(sink.Close()) }()
+
+	chk.NoErr(sink.WriteEntry(&Entry{Level: WarnLevel, Message: "udp"}))
+	chk.Str(readPacket(chk, server), "W: udp\n")
+	chk.NoErr(sink.Flush())
+	chk.Uint64(sink.Sent(), 1)
+
+	_, err = NewNetworkSink("unix", "/tmp/sock", NetworkOptions{})
+	chk.Err(err, "unsupported network: unix")
+}
+
+func Test_SzLog_NetworkSinkReconnect(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	addr := freeAddress(chk)
+	sink, err := NewNetworkSink("tcp", addr, NetworkOptions{
+		Formatter:  new(TextFormatter),
+		BufferSize: 2,
+		MinBackoff: 10 * time.Millisecond,
+		MaxBackoff: 40 * time.Millisecond,
+	})
+	chk.NoErr(err)
+	defer func() { chk.NoErr(sink.Close()) }()
+
+	// While disconnected logging does not block, the oldest entries are
+	// dropped once the buffer is full and Flush reports the failure.
+	logger := New(InfoLevel, log.New(io.Discard, "", 0))
+	chk.NoErr(logger.AddSink(sink))
+	logger.Error("1")
+	chk.Err(sink.Flush(), "network sink not connected")
+	for _, msg := range []string{"2", "3", "4", "5"} {
+		logger.Error(msg)
+	}
+	chk.Err(sink.Flush(), "network sink not connected")
+	chk.Uint64(sink.Sent(), 0)
+
+	server, err := net.Listen("tcp", addr)
+	chk.NoErr(err)
+	defer func() { chk.NoErr(server.Close()) }()
+	conn, err := server.Accept()
+	chk.NoErr(err)
+	defer func() { chk.NoErr(conn.Close()) }()
+
+	// The in flight entry and the last buffered entries are delivered.
+	r := bufio.NewReader(conn)
+	for _, want := range []string{"E: 1\n", "E: 4\n", "E: 5\n"} {
+		line, err := r.ReadString('\n')
+		chk.NoErr(err)
+		chk.Str(line, want)
+	}
+	chk.NoErr(sink.Flush())
+	chk.Uint64(sink.Sent(), 3)
+	chk.Uint64(sink.Dropped(), 2)
+}
+
+func Test_SzLog_NetworkSinkCloseDisconnected(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	sink, err := NewNetworkSink("tcp", freeAddress(chk), NetworkOptions{
+		MinBackoff: time.Hour,
+	})
+	chk.NoErr(err)
+
+	chk.NoErr(sink.WriteEntry(&Entry{Message: "1"}))
+	chk.NoErr(sink.WriteEntry(&Entry{Message: "2"}))
+	chk.Err(sink.Flush(), "network sink not connected")
+
+	// Close does not wait for the backoff and drops undelivered entries.
+	chk.NoErr(sink.Close())
+	chk.Uint64(sink.Dropped(), 2)
+	chk.NoErr(sink.Flush())
+}
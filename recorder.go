@@ -0,0 +1,114 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"sync"
+)
+
+// flightRecorder retains the most recent entries rejected by any sink so
+// they can be written to those sinks when an error occurs.
+type flightRecorder struct {
+	mu      sync.Mutex
+	entries []recorded
+	next    int // Index the next entry is recorded at.
+	full    bool
+}
+
+// recorded is an entry retained by a flightRecorder with the sinks that
+// rejected it.
+type recorded struct {
+	entry    *Entry
+	rejected []Sink
+}
+
+// record retains the entry and the sinks that rejected it replacing the
+// oldest if the recorder is full.
+func (r *flightRecorder) record(entry *Entry, rejected []Sink) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[r.next] = recorded{entry: entry, rejected: rejected}
+	r.next++
+	if r.next == len(r.entries) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// replay writes the recorded entries, oldest first, to each of the current
+// sinks that rejected them regardless of its level and then clears the
+// recorder.
+func (r *flightRecorder) replay(sinks []levelSink) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ordered []recorded
+	if r.full {
+		ordered = append(ordered, r.entries[r.next:]...)
+	}
+	ordered = append(ordered, r.entries[:r.next]...)
+	for _, rec := range ordered {
+		for _, s := range sinks {
+			if rec.rejectedBy(s.Sink) {
+				_ = s.WriteEntry(rec.entry)
+			}
+		}
+	}
+	for i := range r.entries {
+		r.entries[i] = recorded{}
+	}
+	r.next = 0
+	r.full = false
+}
+
+// rejectedBy returns true if the sink rejected the recorded entry.
+func (rec recorded) rejectedBy(sink Sink) bool {
+	for _, s := range rec.rejected {
+		if s == sink {
+			return true
+		}
+	}
+	return false
+}
+
+// WithFlightRecorder returns a child szLog.Logger recording up to size of its
+// most recent messages that any sink rejects.  When the child, or any of its
+// own children, logs an error, fatal or panic message the recorded messages
+// are first written to the sinks that rejected them regardless of level and
+// then discarded.  As messages at every level are recorded all levels are
+// enabled for the child.  Each call creates a separate recorder so a child
+// per request (see NewContext) keeps only that request's history.  A size
+// less than one returns a child without a flight recorder.
+func (logger *Logger) WithFlightRecorder(size int) *Logger {
+	var recorder *flightRecorder
+	if size > 0 {
+		recorder = &flightRecorder{entries: make([]recorded, size)}
+	}
+	return logger.child(logger.fields, logger.callerSkip, recorder)
+}
+
+// WithFlightRecorder returns a child of the standard szLog.Logger recording
+// up to size of its most recent messages that any sink rejects.  When the
+// child logs an error, fatal or panic message the recorded messages are
+// first written to the sinks that rejected them regardless of level and then
+// discarded.  A size less than one returns a child without a flight recorder.
+func WithFlightRecorder(size int) *Logger {
+	return std.WithFlightRecorder(size)
+}
@@ -0,0 +1,142 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+
+	"github.com/dancsecs/szTest"
+)
+
+func Test_SzLog_FlightRecorder(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	errOnly := new(bytes.Buffer)
+	logger := New(WarnLevel, log.New(buf, "", 0))
+	chk.NoErr(logger.AddWriterAt(ErrorLevel, errOnly, "", 0))
+
+	recording := logger.WithFlightRecorder(3)
+	chk.False(logger.IsDebug())
+	chk.True(recording.IsTrace())
+	chk.True(recording.Enabled(TraceLevel))
+
+	recording.Trace("trace 1")
+	recording.Debugf("debug %d", 2)
+	recording.With("k", "v").Infow("info 3")
+	recording.Warn("warn")
+	recording.Debug("debug 4")
+	logger.Debug("not recorded")
+	recording.Error("failed")
+	recording.Error("again")
+
+	// The warning is recorded as the error only sink rejected it but is
+	// only replayed to that sink.
+	chk.Str(buf.String(), ""+
+		"W: warn\n"+
+		"I: info 3 k=v\n"+
+		"D: debug 4\n"+
+		"E: failed\n"+
+		"E: again\n",
+	)
+	chk.Str(errOnly.String(), ""+
+		"I: info 3 k=v\n"+
+		"W: warn\n"+
+		"D: debug 4\n"+
+		"E: failed\n"+
+		"E: again\n",
+	)
+}
+
+func Test_SzLog_FlightRecorderPerSinkLevel(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	ring := NewRingSink(10)
+	logger := New(WarnLevel, log.New(buf, "", 0))
+	chk.NoErr(logger.AddSinkAt(DebugLevel, ring))
+
+	recording := logger.WithFlightRecorder(10)
+	recording.Debug("debug")
+	recording.Trace("trace")
+	recording.Error("boom")
+
+	// The debug entry accepted by the ring is still replayed to the text
+	// sink while the ring receives only the trace entry it rejected.
+	chk.Str(buf.String(), ""+
+		"D: debug\n"+
+		"T: trace\n"+
+		"E: boom\n",
+	)
+	snapshot := ring.Snapshot()
+	chk.Int(len(snapshot), 3)
+	chk.Str(snapshot[0].Message, "debug")
+	chk.Str(snapshot[1].Message, "trace")
+	chk.Str(snapshot[2].Message, "boom")
+}
+
+func Test_SzLog_FlightRecorderSeparate(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	logger := New(InfoLevel, log.New(buf, "", 0))
+	logger.SetExitFunc(func(int) {})
+
+	request1 := logger.WithFlightRecorder(10).With("req", 1)
+	request2 := logger.WithFlightRecorder(10).With("req", 2)
+	ctx := NewContext(context.Background(), request1)
+
+	DebugCtx(ctx, "one")
+	request2.Debug("two")
+	request1.WithCallerSkip(0).Info("info")
+	FromContext(ctx).Fatal("fatal")
+	chk.Panic(func() { request2.Panic("panic") }, "panic")
+
+	chk.False(logger.WithFlightRecorder(0).IsDebug())
+	chk.False(request1.WithFlightRecorder(-1).IsDebug())
+
+	chk.Str(buf.String(), ""+
+		"I: info req=1\n"+
+		"D: one req=1\n"+
+		"F: fatal req=1\n"+
+		"D: two req=2\n"+
+		"P: panic req=2\n",
+	)
+}
+
+func Test_SzLog_Default_FlightRecorder(t *testing.T) {
+	chk := szTest.CaptureLog(t)
+	defer chk.Release()
+
+	recording := WithFlightRecorder(5)
+	recording.Info("info")
+	Info("not recorded")
+	recording.Errorf("failed %s", "here")
+
+	chk.Log("" +
+		"I: info\n" +
+		"E: failed here\n" +
+		"")
+}
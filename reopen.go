@@ -0,0 +1,171 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// Reopener is implemented by sinks and writers able to reopen their
+// underlying file, typically after it has been moved by an external tool
+// such as logrotate.
+type Reopener interface {
+	Reopen() error
+}
+
+// LogFile is an io.Writer appending to the file at a path that can be
+// reopened on demand.  It is safe for concurrent use and may be added to a
+// szLog.Logger with AddWriter like any other io.Writer.
+type LogFile struct {
+	path string
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewLogFile opens (or creates) the file at path for appending.
+func NewLogFile(path string) (*LogFile, error) {
+	f, err := openLogFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &LogFile{path: path, file: f}, nil
+}
+
+// openLogFile opens (or creates) the file at path for appending.
+func openLogFile(path string) (*os.File, error) {
+	return os.OpenFile( //nolint:gosec // Path provided by caller.
+		path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, logFilePerm,
+	)
+}
+
+// Path returns the path of the log file.
+func (l *LogFile) Path() string {
+	return l.path
+}
+
+// Write appends p to the log file.
+func (l *LogFile) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return 0, os.ErrClosed
+	}
+	return l.file.Write(p)
+}
+
+// Reopen closes the current file and opens the path again creating a new
+// file if the old one has been moved or removed.  Should the path fail to
+// open the current file remains in use.
+func (l *LogFile) Reopen() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return os.ErrClosed
+	}
+	f, err := openLogFile(l.path)
+	if err != nil {
+		return err
+	}
+	err = l.file.Close()
+	l.file = f
+	return err
+}
+
+// Close closes the log file.  Subsequent writes return os.ErrClosed.
+func (l *LogFile) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return os.ErrClosed
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// Reopen calls Reopen on every sink, or io.Writer underlying a sink, that
+// implements Reopener returning the first error encountered.
+func (logger *Logger) Reopen() error {
+	var firstErr error
+	for _, s := range logger.core().sinks() {
+		r, ok := s.Sink.(Reopener)
+		if !ok {
+			if w, isWriter := s.Sink.(writerSink); isWriter {
+				r, ok = w.Writer().(Reopener)
+			}
+		}
+		if ok {
+			if err := r.Reopen(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// ReopenOnSIGHUP installs a handler calling Reopen each time the process
+// receives a SIGHUP.  Any error is logged to the selected szLog.Logger.  The
+// returned function removes the handler.
+func (logger *Logger) ReopenOnSIGHUP() (stop func()) {
+	sigs := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sigs, syscall.SIGHUP)
+
+	go func() {
+		for {
+			select {
+			case <-sigs:
+				if err := logger.Reopen(); err != nil {
+					logger.Error("Reopen caused: ", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(sigs)
+			close(done)
+		})
+	}
+}
+
+// Reopen calls Reopen on every sink, or io.Writer underlying a sink, of the
+// standard szLog.Logger that implements Reopener returning the first error
+// encountered.
+func Reopen() error {
+	return std.Reopen()
+}
+
+// ReopenOnSIGHUP installs a handler calling Reopen on the standard
+// szLog.Logger each time the process receives a SIGHUP.  The returned
+// function removes the handler.
+func ReopenOnSIGHUP() (stop func()) {
+	return std.ReopenOnSIGHUP()
+}
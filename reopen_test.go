@@ -0,0 +1,128 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/dancsecs/szTest"
+)
+
+func Test_SzLog_LogFileReopen(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	dir := chk.CreateTmpDir()
+	fPath := filepath.Join(dir, "app.log")
+	movedPath := filepath.Join(dir, "app.log.moved")
+
+	f, err := NewLogFile(fPath)
+	chk.NoErr(err)
+	chk.Str(f.Path(), fPath)
+
+	logger := New(ErrorLevel, log.New(f, "", 0))
+	logger.Error("before move")
+	chk.NoErr(os.Rename(fPath, movedPath))
+	logger.Error("after move")
+	chk.NoErr(logger.Reopen())
+	logger.Error("after reopen")
+	chk.NoErr(f.Close())
+
+	chk.Str(readFile(chk, movedPath), "E: before move\nE: after move\n")
+	chk.Str(readFile(chk, fPath), "E: after reopen\n")
+
+	_, err = f.Write([]byte("closed"))
+	chk.Err(err, os.ErrClosed.Error())
+	chk.Err(f.Reopen(), os.ErrClosed.Error())
+	chk.Err(f.Close(), os.ErrClosed.Error())
+	chk.Err(logger.Reopen(), os.ErrClosed.Error())
+}
+
+func Test_SzLog_RotatingFileReopen(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	dir := chk.CreateTmpDir()
+	fPath := filepath.Join(dir, "app.log")
+	movedPath := filepath.Join(dir, "app.log.moved")
+
+	r, err := NewRotatingFile(fPath, RotateOptions{})
+	chk.NoErr(err)
+
+	logger := New(ErrorLevel, log.New(new(bytes.Buffer), "", 0))
+	chk.NoErr(logger.AddWriter(r, "", 0))
+	logger.Error("before move")
+	chk.NoErr(os.Rename(fPath, movedPath))
+	chk.NoErr(logger.Reopen())
+	logger.Error("after reopen")
+	chk.NoErr(r.Close())
+
+	chk.Str(readFile(chk, movedPath), "E: before move\n")
+	chk.Str(readFile(chk, fPath), "E: after reopen\n")
+	chk.Err(r.Reopen(), os.ErrClosed.Error())
+}
+
+func Test_SzLog_ReopenOnSIGHUP(t *testing.T) {
+	chk := szTest.CaptureLog(t)
+	defer chk.Release()
+
+	dir := chk.CreateTmpDir()
+	fPath := filepath.Join(dir, "app.log")
+	movedPath := filepath.Join(dir, "app.log.moved")
+
+	f, err := NewLogFile(fPath)
+	chk.NoErr(err)
+	defer Close(f)
+
+	chk.NoErr(AddWriter(f, "", 0))
+	defer func() { chk.NoErr(RemoveWriter(f)) }()
+
+	stop := ReopenOnSIGHUP()
+	defer stop()
+
+	Error("before signal")
+	chk.NoErr(os.Rename(fPath, movedPath))
+
+	p, err := os.FindProcess(os.Getpid())
+	chk.NoErr(err)
+	if err := p.Signal(syscall.SIGHUP); err != nil {
+		t.Skip("cannot send SIGHUP: ", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	_, err = os.Stat(fPath)
+	for err != nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		_, err = os.Stat(fPath)
+	}
+	chk.NoErr(err)
+
+	Error("after signal")
+	stop()
+
+	chk.Str(readFile(chk, movedPath), "E: before signal\n")
+	chk.Str(readFile(chk, fPath), "E: after signal\n")
+	chk.Log("E: before signal\nE: after signal\n")
+}
@@ -0,0 +1,158 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RingSink is a Sink keeping the most recent entries in memory.  Added with
+// AddSinkAt it may hold more verbose entries than are otherwise logged, for
+// example at DebugLevel while other sinks filter at WarnLevel, allowing them
+// to be examined when something goes wrong.  It is an http.Handler serving
+// its entries.
+type RingSink struct {
+	mu      sync.Mutex
+	entries []*Entry
+	next    int // Index the next entry is written to.
+	full    bool
+}
+
+// NewRingSink creates a RingSink keeping the last size entries.
+func NewRingSink(size int) *RingSink {
+	if size < 1 {
+		size = 1
+	}
+	return &RingSink{entries: make([]*Entry, size)}
+}
+
+// WriteEntry records the entry replacing the oldest if the RingSink is full.
+func (s *RingSink) WriteEntry(entry *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[s.next] = entry
+	s.next++
+	if s.next == len(s.entries) {
+		s.next = 0
+		s.full = true
+	}
+	return nil
+}
+
+// Snapshot returns a copy of the recorded entries oldest first.
+func (s *RingSink) Snapshot() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ordered []*Entry
+	if s.full {
+		ordered = append(ordered, s.entries[s.next:]...)
+	}
+	ordered = append(ordered, s.entries[:s.next]...)
+	snapshot := make([]Entry, len(ordered))
+	for i, e := range ordered {
+		snapshot[i] = *e
+	}
+	return snapshot
+}
+
+// Reset discards all recorded entries.
+func (s *RingSink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.entries {
+		s.entries[i] = nil
+	}
+	s.next = 0
+	s.full = false
+}
+
+// ServeHTTP implements http.Handler writing a snapshot of the entries, oldest
+// first.  They are written as a JSON array if the "format" query parameter is
+// "json" or the request accepts application/json, and as text lines each
+// preceded by its time otherwise.  A "level" query parameter limits the
+// entries to those permitted by the level.
+func (s *RingSink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	threshold := TraceLevel
+	if text := r.URL.Query().Get("level"); text != "" {
+		level, err := ParseLevel(text)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		threshold = level
+	}
+
+	format := r.URL.Query().Get("format")
+	asJSON := format == "json" || (format == "" &&
+		strings.Contains(r.Header.Get("Accept"), "application/json"))
+
+	var b []byte
+	if asJSON {
+		b = append(b, '[')
+	}
+	first := true
+	for _, entry := range s.Snapshot() {
+		entry := entry
+		if !entry.Level.permittedBy(threshold) {
+			continue
+		}
+		switch {
+		case !asJSON:
+			b = appendRingText(b, &entry)
+		case first:
+			b = appendJSON(b, &entry)
+		default:
+			b = appendJSON(append(b, ",\n"...), &entry)
+		}
+		if asJSON {
+			b = b[:len(b)-1] // Drop the newline ending each JSON line.
+		}
+		first = false
+	}
+
+	if asJSON {
+		b = append(b, "]\n"...)
+		w.Header().Set("Content-Type", "application/json")
+	} else {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	_, _ = w.Write(b)
+}
+
+// appendRingText appends the entry's time and its text form as produced by
+// the Formatter of the szLog.Logger that wrote it to b.
+func appendRingText(b []byte, entry *Entry) []byte {
+	formatter := entry.formatter
+	if formatter == nil {
+		formatter = defaultFormatter
+	}
+	text, err := formatter.Format(entry)
+	if err != nil {
+		text = []byte(err.Error())
+	}
+	b = append(b, entry.Time.Format(time.RFC3339Nano)+" "...)
+	b = append(b, strings.TrimSuffix(string(text), "\n")...)
+	return append(b, '\n')
+}
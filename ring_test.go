@@ -0,0 +1,158 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dancsecs/szTest"
+)
+
+// ringTime is the time of the nth entry written in ring sink tests.
+func ringTime(n int) time.Time {
+	return time.Date(2023, 11, 19, 10, 20, n, 0, time.UTC)
+}
+
+// serveRing returns the response body of the RingSink for the request.
+func serveRing(
+	chk *szTest.Chk, s *RingSink, target, accept string,
+) (int, string, string) {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	body, err := io.ReadAll(rec.Result().Body)
+	chk.NoErr(err)
+	return rec.Code, rec.Header().Get("Content-Type"), string(body)
+}
+
+func Test_SzLog_RingSinkSnapshot(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	s := NewRingSink(3)
+	chk.Int(len(s.Snapshot()), 0)
+	chk.Int(len(NewRingSink(0).entries), 1)
+
+	for i := 1; i <= 5; i++ {
+		chk.NoErr(s.WriteEntry(&Entry{Message: strconv.Itoa(i)}))
+		snapshot := s.Snapshot()
+		oldest := 1
+		if i > 3 {
+			oldest = i - 2
+		}
+		chk.Int(len(snapshot), i-oldest+1)
+		chk.Str(snapshot[0].Message, strconv.Itoa(oldest))
+		chk.Str(snapshot[len(snapshot)-1].Message, strconv.Itoa(i))
+	}
+
+	s.Reset()
+	chk.Int(len(s.Snapshot()), 0)
+	chk.NoErr(s.WriteEntry(&Entry{Message: "after"}))
+	chk.Int(len(s.Snapshot()), 1)
+}
+
+func Test_SzLog_RingSinkLevel(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	ring := NewRingSink(10)
+	logger := New(WarnLevel, log.New(io.Discard, "", 0))
+	chk.NoErr(logger.AddSinkAt(DebugLevel, ring))
+
+	logger.Trace("trace")
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warn("warn")
+
+	snapshot := ring.Snapshot()
+	chk.Int(len(snapshot), 3)
+	chk.Str(snapshot[0].Message, "debug")
+	chk.Str(snapshot[2].Message, "warn")
+}
+
+func Test_SzLog_RingSinkHTTP(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	ring := NewRingSink(2)
+	chk.NoErr(ring.WriteEntry(&Entry{Time: ringTime(0), Message: "dropped"}))
+	chk.NoErr(ring.WriteEntry(&Entry{
+		Time: ringTime(1), Level: DebugLevel, Message: "line 1\nline 2",
+	}))
+	chk.NoErr(ring.WriteEntry(&Entry{
+		Time:      ringTime(2),
+		Level:     ErrorLevel,
+		Message:   "failed",
+		Fields:    []Field{Int("n", 1)},
+		formatter: new(TextFormatter).WithLabel(ErrorLevel, "[E] "),
+	}))
+
+	code, contentType, body := serveRing(chk, ring, "/", "")
+	chk.Int(code, http.StatusOK)
+	chk.Str(contentType, "text/plain; charset=utf-8")
+	chk.Str(body, ""+
+		"2023-11-19T10:20:01Z D: line 1\n+  line 2\n"+
+		"2023-11-19T10:20:02Z [E] failed n=1\n",
+	)
+
+	_, _, body = serveRing(chk, ring, "/?level=warn", "")
+	chk.Str(body, "2023-11-19T10:20:02Z [E] failed n=1\n")
+
+	code, contentType, body = serveRing(chk, ring, "/", "application/json")
+	chk.Int(code, http.StatusOK)
+	chk.Str(contentType, "application/json")
+	chk.Str(body, ""+
+		`[{"time":"2023-11-19T10:20:01Z","level":"debug",`+
+		`"msg":"line 1\nline 2"},`+"\n"+
+		`{"time":"2023-11-19T10:20:02Z","level":"error","msg":"failed",`+
+		`"fields":{"n":1}}]`+"\n",
+	)
+	var decoded []map[string]any
+	chk.NoErr(json.Unmarshal([]byte(body), &decoded))
+	chk.Int(len(decoded), 2)
+
+	_, _, body = serveRing(chk, ring, "/?format=json&level=error", "")
+	chk.Str(body, ""+
+		`[{"time":"2023-11-19T10:20:02Z","level":"error","msg":"failed",`+
+		`"fields":{"n":1}}]`+"\n",
+	)
+
+	_, _, body = serveRing(chk, ring, "/?format=text", "application/json")
+	chk.Str(body, ""+
+		"2023-11-19T10:20:01Z D: line 1\n+  line 2\n"+
+		"2023-11-19T10:20:02Z [E] failed n=1\n",
+	)
+
+	_, _, body = serveRing(chk, NewRingSink(1), "/?format=json", "")
+	chk.Str(body, "[]\n")
+
+	code, _, body = serveRing(chk, ring, "/?level=loud", "")
+	chk.Int(code, http.StatusBadRequest)
+	chk.Str(body, "invalid level: \"loud\"\n")
+}
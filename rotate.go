@@ -0,0 +1,311 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RotateOptions defines when a RotatingFile rolls over and what it keeps.
+type RotateOptions struct {
+	// MaxSize is the size in bytes a file may reach before it is rolled
+	// over.  A write is never split so a file may exceed this size by at most
+	// one write.  Zero disables size based rotation.
+	MaxSize int64
+
+	// Interval rolls the file over on each wall-clock boundary that is a
+	// multiple of the interval since the zero time.  For example time.Hour
+	// rolls on the hour and 24*time.Hour at midnight UTC.  Zero disables
+	// time based rotation.
+	Interval time.Duration
+
+	// MaxBackups is the number of rolled files kept named path.1 (the most
+	// recent) through path.MaxBackups.  At least one is always kept.
+	MaxBackups int
+
+	// Compress gzips rolled files adding a .gz extension.  Compression is
+	// done in the background so as not to delay writes.  A file that fails
+	// to compress is kept, and shifted, uncompressed.
+	Compress bool
+
+	// OnError is called with errors rolling the file over, or compressing
+	// it, that do not prevent the line being written and so are not
+	// returned by Write.  When nil they are written to os.Stderr.
+	OnError func(err error)
+}
+
+// RotatingFile is an io.Writer appending to a file that is rolled over by
+// size or time.  It is safe for concurrent use and each Write is completed
+// in full before any rotation so log lines are never lost or split.  Should
+// the active file fail to open it is retried on the next Write.  It may be
+// added to a szLog.Logger with AddWriter like any other io.Writer.
+type RotatingFile struct {
+	path string
+	opts RotateOptions
+	now  func() time.Time
+
+	mu          sync.Mutex
+	file        *os.File // Nil if closed or the last open failed.
+	closed      bool
+	size        int64
+	rollAt      time.Time
+	compressing chan struct{} // Closed when background compression ends.
+}
+
+// NewRotatingFile opens (or creates) the file at path for appending returning
+// a RotatingFile rolling it over as defined by the options.
+func NewRotatingFile(path string, opts RotateOptions) (*RotatingFile, error) {
+	if opts.MaxBackups < 1 {
+		opts.MaxBackups = 1
+	}
+	r := &RotatingFile{
+		path: path,
+		opts: opts,
+		now:  time.Now,
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Path returns the path of the active log file.
+func (r *RotatingFile) Path() string {
+	return r.path
+}
+
+// open opens the active file for appending recording its current size and
+// the next time boundary.  It must be called with mu held (or before the
+// RotatingFile is shared).
+func (r *RotatingFile) open() error {
+	f, err := openLogFile(r.path)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	r.file = f
+	r.size = info.Size()
+	if r.opts.Interval > 0 {
+		r.rollAt = r.now().Truncate(r.opts.Interval).Add(r.opts.Interval)
+	}
+	return nil
+}
+
+// Write appends p to the active file first rolling it over if the write
+// would exceed MaxSize or a time boundary has passed, or opening it again if
+// a previous open failed.  An error rolling the file over is passed to
+// OnError rather than returned unless it leaves no file to write to.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+	var rotateErr error
+	if r.file == nil {
+		rotateErr = r.open()
+	} else if r.needsRotation(int64(len(p))) {
+		rotateErr = r.rotate()
+	}
+	if r.file == nil {
+		return 0, rotateErr
+	}
+	if rotateErr != nil {
+		r.reportErr(rotateErr)
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// reportErr passes an error not returned by Write to OnError.
+func (r *RotatingFile) reportErr(err error) {
+	if r.opts.OnError != nil {
+		r.opts.OnError(err)
+		return
+	}
+	_, _ = io.WriteString(
+		os.Stderr, "szLog: rotating "+r.path+": "+err.Error()+"\n",
+	)
+}
+
+// needsRotation returns true if the active file should be rolled over
+// before writing the provided number of bytes.
+func (r *RotatingFile) needsRotation(n int64) bool {
+	if r.opts.MaxSize > 0 && r.size > 0 && r.size+n > r.opts.MaxSize {
+		return true
+	}
+	return r.opts.Interval > 0 && !r.now().Before(r.rollAt)
+}
+
+// Rotate rolls the active file over immediately.
+func (r *RotatingFile) Rotate() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return os.ErrClosed
+	}
+	return r.rotate()
+}
+
+// rotate closes the active file, shifts the backups discarding the oldest,
+// moves the active file to path.1 (compressing it in the background if
+// requested) and opens a new active file.  Any previous compression is
+// waited for so the backups are shifted intact.  It must be called with mu
+// held.
+func (r *RotatingFile) rotate() error {
+	r.waitCompression()
+
+	var err error
+	if r.file != nil {
+		err = r.file.Close()
+		r.file = nil
+	}
+
+	// Uncompressed backups left by a failed compression are shifted along
+	// with the compressed ones so they are not overwritten.
+	exts := []string{""}
+	if r.opts.Compress {
+		exts = append(exts, ".gz")
+	}
+	for _, ext := range exts {
+		oldest := r.backupPath(r.opts.MaxBackups) + ext
+		if rmErr := os.Remove(oldest); rmErr != nil && !os.IsNotExist(rmErr) {
+			err = firstErr(err, rmErr)
+		}
+	}
+	for i := r.opts.MaxBackups - 1; i > 0; i-- {
+		for _, ext := range exts {
+			mvErr := os.Rename(r.backupPath(i)+ext, r.backupPath(i+1)+ext)
+			if mvErr != nil && !os.IsNotExist(mvErr) {
+				err = firstErr(err, mvErr)
+			}
+		}
+	}
+	if mvErr := os.Rename(r.path, r.backupPath(1)); mvErr != nil {
+		err = firstErr(err, mvErr)
+	} else if r.opts.Compress {
+		done := make(chan struct{})
+		r.compressing = done
+		go func(path string) {
+			defer close(done)
+			if gzErr := compressFile(path); gzErr != nil {
+				r.reportErr(gzErr)
+			}
+		}(r.backupPath(1))
+	}
+
+	return firstErr(r.open(), err)
+}
+
+// waitCompression waits for any background compression to finish.
+func (r *RotatingFile) waitCompression() {
+	if r.compressing != nil {
+		<-r.compressing
+		r.compressing = nil
+	}
+}
+
+// backupPath returns the path of the numbered backup.
+func (r *RotatingFile) backupPath(n int) string {
+	return r.path + "." + strconv.Itoa(n)
+}
+
+// Reopen closes the active file and opens its path again without rolling it
+// over.  It allows an externally moved file to be replaced.
+func (r *RotatingFile) Reopen() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return os.ErrClosed
+	}
+	var err error
+	if r.file != nil {
+		err = r.file.Close()
+		r.file = nil
+	}
+	return firstErr(r.open(), err)
+}
+
+// Close closes the active file after waiting for any background
+// compression.  Subsequent writes return os.ErrClosed.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return os.ErrClosed
+	}
+	r.closed = true
+	r.waitCompression()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// compressFile gzips the file at path to path.gz removing the original once
+// the compressed copy is complete.
+func compressFile(path string) error {
+	src, err := os.Open(path) //nolint:gosec // Our own rolled file.
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+
+	dst, err := os.OpenFile(
+		path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, logFilePerm,
+	)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	err = firstErr(err, zw.Close())
+	err = firstErr(err, dst.Close())
+	if err != nil {
+		_ = os.Remove(path + ".gz")
+		return err
+	}
+	return os.Remove(path)
+}
+
+// firstErr returns the first non nil error.
+func firstErr(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
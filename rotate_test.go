@@ -0,0 +1,284 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dancsecs/szTest"
+)
+
+func readFile(chk *szTest.Chk, path string) string {
+	b, err := os.ReadFile(path)
+	chk.NoErr(err)
+	return string(b)
+}
+
+func Test_SzLog_RotatingFileBySize(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	fPath := filepath.Join(chk.CreateTmpDir(), "app.log")
+	chk.NoErr(os.WriteFile(fPath, []byte("E: line 0\n"), 0o600))
+
+	r, err := NewRotatingFile(fPath, RotateOptions{MaxSize: 30, MaxBackups: 2})
+	chk.NoErr(err)
+	chk.Str(r.Path(), fPath)
+
+	logger := New(ErrorLevel, log.New(r, "", 0))
+	for i := 1; i <= 7; i++ {
+		logger.Error("line ", i)
+	}
+	chk.NoErr(r.Close())
+
+	chk.Str(readFile(chk, fPath), "E: line 6\nE: line 7\n")
+	chk.Str(readFile(chk, fPath+".1"), "E: line 3\nE: line 4\nE: line 5\n")
+	chk.Str(readFile(chk, fPath+".2"), "E: line 0\nE: line 1\nE: line 2\n")
+	_, err = os.Stat(fPath + ".3")
+	chk.True(os.IsNotExist(err))
+
+	_, err = r.Write([]byte("closed"))
+	chk.Err(err, os.ErrClosed.Error())
+	chk.Err(r.Rotate(), os.ErrClosed.Error())
+	chk.Err(r.Close(), os.ErrClosed.Error())
+}
+
+func Test_SzLog_RotatingFileByTime(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	now := time.Date(2023, 11, 19, 10, 59, 0, 0, time.UTC)
+
+	fPath := filepath.Join(chk.CreateTmpDir(), "app.log")
+	r, err := NewRotatingFile(fPath, RotateOptions{Interval: time.Hour})
+	chk.NoErr(err)
+	r.now = func() time.Time { return now }
+	chk.NoErr(r.Rotate())
+	chk.Str(readFile(chk, fPath+".1"), "")
+
+	_, err = r.Write([]byte("before\n"))
+	chk.NoErr(err)
+	now = now.Add(time.Minute)
+	_, err = r.Write([]byte("on the hour\n"))
+	chk.NoErr(err)
+	now = now.Add(time.Minute)
+	_, err = r.Write([]byte("after\n"))
+	chk.NoErr(err)
+	chk.NoErr(r.Close())
+
+	chk.Str(readFile(chk, fPath), "on the hour\nafter\n")
+	chk.Str(readFile(chk, fPath+".1"), "before\n")
+}
+
+func Test_SzLog_RotatingFileCompressed(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	fPath := filepath.Join(chk.CreateTmpDir(), "app.log")
+	r, err := NewRotatingFile(fPath, RotateOptions{
+		MaxSize:    10,
+		MaxBackups: 2,
+		Compress:   true,
+	})
+	chk.NoErr(err)
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		_, err = r.Write([]byte(line))
+		chk.NoErr(err)
+	}
+	chk.NoErr(r.Close())
+
+	readGz := func(path string) string {
+		f, err := os.Open(path)
+		chk.NoErr(err)
+		defer Close(f)
+		zr, err := gzip.NewReader(f)
+		chk.NoErr(err)
+		b, err := io.ReadAll(zr)
+		chk.NoErr(err)
+		return string(b)
+	}
+
+	chk.Str(readFile(chk, fPath), "third\n")
+	chk.Str(readGz(fPath+".1.gz"), "second\n")
+	chk.Str(readGz(fPath+".2.gz"), "first\n")
+	_, err = os.Stat(fPath + ".1")
+	chk.True(os.IsNotExist(err))
+}
+
+func Test_SzLog_RotatingFileCompressError(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	// Directories in the way of compressed backups make compression fail.
+	fPath := filepath.Join(chk.CreateTmpDir(), "app.log")
+	chk.NoErr(os.Mkdir(fPath+".1.gz", 0o700))
+	chk.NoErr(os.MkdirAll(filepath.Join(fPath+".2.gz", "blocked"), 0o700))
+
+	var reported []error
+	var mu sync.Mutex
+	r, err := NewRotatingFile(fPath, RotateOptions{
+		MaxSize:    10,
+		MaxBackups: 2,
+		Compress:   true,
+		OnError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			reported = append(reported, err)
+		},
+	})
+	chk.NoErr(err)
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		_, err = r.Write([]byte(line))
+		chk.NoErr(err)
+	}
+	chk.NoErr(r.Close())
+
+	// The uncompressed backups are shifted rather than overwritten.
+	mu.Lock()
+	chk.True(len(reported) > 0)
+	mu.Unlock()
+	chk.Str(readFile(chk, fPath), "third\n")
+	chk.Str(readFile(chk, fPath+".1"), "second\n")
+	chk.Str(readFile(chk, fPath+".2"), "first\n")
+}
+
+func Test_SzLog_RotatingFileConcurrent(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	const (
+		writers = 8
+		lines   = 100
+	)
+
+	fPath := filepath.Join(chk.CreateTmpDir(), "app.log")
+	r, err := NewRotatingFile(fPath, RotateOptions{
+		MaxSize:    500,
+		MaxBackups: writers * lines,
+	})
+	chk.NoErr(err)
+
+	logger := New(ErrorLevel, log.New(r, "", 0))
+
+	var wg sync.WaitGroup
+	wg.Add(writers)
+	for w := 0; w < writers; w++ {
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < lines; i++ {
+				logger.Errorf("writer %d line %03d", w, i)
+			}
+		}(w)
+	}
+	wg.Wait()
+	chk.NoErr(r.Close())
+
+	files, err := filepath.Glob(fPath + "*")
+	chk.NoErr(err)
+	chk.True(len(files) > 1)
+
+	got := []string{}
+	for _, f := range files {
+		content := readFile(chk, f)
+		chk.True(strings.HasSuffix(content, "\n"), f)
+		got = append(got, strings.Split(strings.TrimSuffix(content, "\n"), "\n")...)
+	}
+	want := []string{}
+	for w := 0; w < writers; w++ {
+		for i := 0; i < lines; i++ {
+			want = append(want, fmt.Sprintf("E: writer %d line %03d", w, i))
+		}
+	}
+	sort.Strings(got)
+	sort.Strings(want)
+	chk.StrSlice(got, want)
+}
+
+func Test_SzLog_RotatingFileOpenError(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	fPath := filepath.Join(chk.CreateTmpDir(), "missing", "app.log")
+	r, err := NewRotatingFile(fPath, RotateOptions{})
+	chk.Err(err, "open "+fPath+": no such file or directory")
+	chk.True(r == nil)
+}
+
+func Test_SzLog_RotatingFileRotateError(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	fPath := filepath.Join(chk.CreateTmpDir(), "app.log")
+	chk.NoErr(os.MkdirAll(filepath.Join(fPath+".2", "blocked"), 0o700))
+
+	var reported []error
+	r, err := NewRotatingFile(fPath, RotateOptions{
+		MaxSize:    5,
+		MaxBackups: 2,
+		OnError:    func(err error) { reported = append(reported, err) },
+	})
+	chk.NoErr(err)
+
+	for _, line := range []string{"one\n", "two\n", "LINE\n"} {
+		n, err := r.Write([]byte(line))
+		chk.NoErr(err)
+		chk.Int(n, len(line))
+	}
+	chk.NoErr(r.Close())
+
+	// The backup could not be shifted onto the directory but no line is lost.
+	chk.True(len(reported) > 0)
+	chk.Str(readFile(chk, fPath), "LINE\n")
+	chk.Str(readFile(chk, fPath+".1"), "two\n")
+}
+
+func Test_SzLog_RotatingFileOpenRetried(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	dir := filepath.Join(chk.CreateTmpDir(), "logs")
+	chk.NoErr(os.Mkdir(dir, 0o700))
+	fPath := filepath.Join(dir, "app.log")
+	r, err := NewRotatingFile(fPath, RotateOptions{})
+	chk.NoErr(err)
+
+	chk.NoErr(os.RemoveAll(dir))
+	chk.Err(r.Reopen(), "open "+fPath+": no such file or directory")
+
+	_, err = r.Write([]byte("lost\n"))
+	chk.Err(err, "open "+fPath+": no such file or directory")
+
+	chk.NoErr(os.Mkdir(dir, 0o700))
+	_, err = r.Write([]byte("found\n"))
+	chk.NoErr(err)
+	chk.NoErr(r.Close())
+	chk.Str(readFile(chk, fPath), "found\n")
+}
@@ -0,0 +1,350 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry represents a single message as passed to each Sink.
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  []Field
+	Caller  Caller
+
+	formatter Formatter   // Set by the szLog.Logger writing the entry.
+	source    *log.Logger // Set if written by a log.Logger to a levelWriter.
+}
+
+// Caller identifies the source line a message was logged from.  It is the
+// zero value if the caller could not be determined.
+type Caller struct {
+	PC   uintptr
+	File string
+	Line int
+}
+
+// String returns the caller as the file's final directory and name followed
+// by the line number, for example "szLog/szLog.go:42", or an empty string if
+// the caller is unknown.
+func (c Caller) String() string {
+	if c.File == "" {
+		return ""
+	}
+	file := c.File
+	if i := strings.LastIndexByte(file, '/'); i >= 0 {
+		if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+			file = file[j+1:]
+		}
+	}
+	return file + ":" + strconv.Itoa(c.Line)
+}
+
+// Sink receives every entry permitted by the szLog.Logger it is added to.
+// Sinks are compared for identity when added so should be pointer types.
+type Sink interface {
+	WriteEntry(entry *Entry) error
+}
+
+// Flusher is implemented by sinks able to flush any buffered output.
+type Flusher interface {
+	Flush() error
+}
+
+// writerSink is implemented by sinks writing to an underlying io.Writer
+// allowing duplicate writers to be detected.
+type writerSink interface {
+	Writer() io.Writer
+}
+
+// replaceableSink is implemented by sinks able to produce an equivalent sink
+// writing to a different io.Writer.
+type replaceableSink interface {
+	withWriter(w io.Writer) Sink
+}
+
+// loggerSink is implemented by sinks, such as TextSink and ConsoleSink,
+// writing through a log.Logger.
+type loggerSink interface {
+	Logger() *log.Logger
+	withLogger(logger *log.Logger) Sink
+}
+
+// checkDuplicate returns an error if the new sink duplicates the existing
+// sink or writes to the same underlying io.Writer.
+func checkDuplicate(existing, newSink Sink) error {
+	if existing == newSink {
+		return errors.New("duplicate sink added")
+	}
+	if e, ok := existing.(loggerSink); ok {
+		if n, ok := newSink.(loggerSink); ok && e.Logger() == n.Logger() {
+			return errors.New("duplicate logger added")
+		}
+	}
+	e, eOk := existing.(writerSink)
+	n, nOk := newSink.(writerSink)
+	if eOk && nOk && e.Writer() != nil && e.Writer() == n.Writer() {
+		return errors.New("duplicate os.Writer added")
+	}
+	return nil
+}
+
+// textCallDepth is the call depth passed to log.Logger.Output matching that
+// used by log.Logger.Print.
+const textCallDepth = 2
+
+// TextSink writes entries formatted as text to a log.Logger which adds its
+// own prefix and flags.  Unless given a Formatter of its own it uses that of
+// the szLog.Logger writing the entry.
+type TextSink struct {
+	mu        sync.Mutex
+	logger    *log.Logger
+	formatter Formatter
+	located   *log.Logger // Writes entries whose caller is not on the stack.
+}
+
+// NewTextSink creates a TextSink writing to the provided log.Logger using
+// the Formatter of the szLog.Logger it is added to.
+func NewTextSink(logger *log.Logger) *TextSink {
+	return &TextSink{logger: logger}
+}
+
+// NewFormattedTextSink creates a TextSink writing to the provided log.Logger
+// using the provided Formatter.
+func NewFormattedTextSink(logger *log.Logger, formatter Formatter) *TextSink {
+	return &TextSink{logger: logger, formatter: formatter}
+}
+
+// Logger returns the log.Logger written to by the TextSink.
+func (s *TextSink) Logger() *log.Logger {
+	return s.logger
+}
+
+// Writer returns the io.Writer underlying the TextSink's log.Logger.
+func (s *TextSink) Writer() io.Writer {
+	return s.logger.Writer()
+}
+
+// Flush flushes the underlying io.Writer if it implements Flusher.
+func (s *TextSink) Flush() error {
+	return flushWriter(s.logger.Writer())
+}
+
+// withWriter returns a TextSink with a new log.Logger having the same prefix,
+// flags and Formatter but writing to the provided io.Writer.
+func (s *TextSink) withWriter(w io.Writer) Sink {
+	return NewFormattedTextSink(
+		log.New(w, s.logger.Prefix(), s.logger.Flags()), s.formatter,
+	)
+}
+
+// withLogger returns a TextSink writing to the provided log.Logger using the
+// same Formatter.
+func (s *TextSink) withLogger(logger *log.Logger) Sink {
+	return NewFormattedTextSink(logger, s.formatter)
+}
+
+// WriteEntry writes the formatted entry.  The log.Lshortfile and
+// log.Llongfile flags report the entry's caller.
+func (s *TextSink) WriteEntry(entry *Entry) error {
+	formatter := s.formatter
+	if formatter == nil {
+		formatter = entry.formatter
+	}
+	if formatter == nil {
+		formatter = defaultFormatter
+	}
+	b, err := formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	msg := string(b)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	const fileFlags = log.Lshortfile | log.Llongfile
+	flags := s.logger.Flags()
+	if flags&fileFlags == 0 || entry.Caller.File == "" {
+		return s.logger.Output(textCallDepth, msg)
+	}
+
+	// The log.Logger reports the caller found on the stack so entries written
+	// synchronously are written by it reporting their own caller.
+	if depth, ok := outputDepth(entry.Caller); ok {
+		return s.logger.Output(depth, msg)
+	}
+
+	// Otherwise, as when written asynchronously, the file and line are
+	// written as part of the message by an equivalent log.Logger without the
+	// file flags.  They precede the prefix when log.Lmsgprefix is set.
+	file := entry.Caller.File
+	if flags&log.Lshortfile != 0 {
+		file = file[strings.LastIndexByte(file, '/')+1:]
+	}
+	location := file + ":" + strconv.Itoa(entry.Caller.Line) + ": "
+	prefix := s.logger.Prefix()
+	flags &^= fileFlags
+	if flags&log.Lmsgprefix != 0 {
+		location += prefix
+		prefix = ""
+	}
+	w, l := s.logger.Writer(), s.located
+	if l == nil || l.Writer() != w || l.Prefix() != prefix ||
+		l.Flags() != flags {
+		l = log.New(w, prefix, flags)
+		s.located = l
+	}
+	return l.Output(textCallDepth, location+msg)
+}
+
+// outputDepth returns the call depth passed to log.Logger.Output by
+// TextSink.WriteEntry for it to report the caller or false if the caller is
+// not on the stack.
+func outputDepth(caller Caller) (int, bool) {
+	const maxFrames = 64
+	pcs := make([]uintptr, maxFrames)
+	// Skip runtime.Callers and outputDepth leaving TextSink.WriteEntry first.
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for depth, more := 1, true; more; depth++ {
+		var frame runtime.Frame
+		frame, more = frames.Next()
+		if frame.File == caller.File && frame.Line == caller.Line {
+			return depth, true
+		}
+	}
+	return 0, false
+}
+
+// JSONSink writes each entry as a single line JSON object of the form:
+//
+//	{"time":"...","level":"error","msg":"...","caller":"dir/file.go:42",
+//	  "fields":{"key":value}}
+//
+// The caller is omitted when unknown and the fields object is omitted when
+// the entry has no fields.
+type JSONSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONSink creates a JSONSink writing to the provided io.Writer.
+func NewJSONSink(w io.Writer) *JSONSink {
+	return &JSONSink{w: w}
+}
+
+// Writer returns the io.Writer written to by the JSONSink.
+func (s *JSONSink) Writer() io.Writer {
+	return s.w
+}
+
+// Flush flushes the underlying io.Writer if it implements Flusher.
+func (s *JSONSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return flushWriter(s.w)
+}
+
+// withWriter returns a JSONSink writing to the provided io.Writer.
+func (s *JSONSink) withWriter(w io.Writer) Sink {
+	return NewJSONSink(w)
+}
+
+// WriteEntry writes the entry as a single line JSON object.
+func (s *JSONSink) WriteEntry(entry *Entry) error {
+	b := appendJSON(nil, entry)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.w.Write(b)
+	return err
+}
+
+// flushWriter flushes the io.Writer if it implements Flusher.
+func flushWriter(w io.Writer) error {
+	if f, ok := w.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// appendJSON appends the JSON line representing the entry to b.
+func appendJSON(b []byte, entry *Entry) []byte {
+	b = append(b, `{"time":`...)
+	b = appendJSONValue(b, entry.Time.Format(time.RFC3339Nano))
+	b = append(b, `,"level":`...)
+	b = appendJSONValue(b, entry.Level.String())
+	b = append(b, `,"msg":`...)
+	b = appendJSONValue(b, entry.Message)
+	if entry.Caller.File != "" {
+		b = append(b, `,"caller":`...)
+		b = appendJSONValue(b, entry.Caller.String())
+	}
+	if len(entry.Fields) > 0 {
+		b = append(b, `,"fields":{`...)
+		for i, f := range entry.Fields {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendJSONValue(b, f.Key)
+			b = append(b, ':')
+			b = appendJSONValue(b, jsonValue(f.Value))
+		}
+		b = append(b, '}')
+	}
+	return append(b, "}\n"...)
+}
+
+// jsonValue converts values without a useful JSON encoding into strings.
+func jsonValue(value any) any {
+	switch v := value.(type) {
+	case error:
+		return v.Error()
+	case time.Duration:
+		return v.String()
+	default:
+		return value
+	}
+}
+
+// appendJSONValue appends the JSON encoding of the value to b falling back
+// to an encoded string should the value not be marshalable.  Unlike
+// json.Marshal, HTML characters are not escaped.
+func appendJSONValue(b []byte, value any) []byte {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if enc.Encode(value) != nil {
+		buf.Reset()
+		_ = enc.Encode(fmt.Sprint(value))
+	}
+	return append(b, bytes.TrimSuffix(buf.Bytes(), []byte("\n"))...)
+}
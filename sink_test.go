@@ -0,0 +1,207 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dancsecs/szTest"
+)
+
+var (
+	jsonTime   = regexp.MustCompile(`"time":"[^"]+"`)
+	jsonCaller = regexp.MustCompile(`,"caller":"[^"]+"`)
+)
+
+// normJSON replaces the variable time and removes the caller from JSON
+// lines.
+func normJSON(s string) string {
+	return jsonCaller.ReplaceAllString(
+		jsonTime.ReplaceAllString(s, `"time":"T"`), "",
+	)
+}
+
+// testSink counts the entries written to it.
+type testSink struct {
+	count int
+}
+
+func (s *testSink) WriteEntry(_ *Entry) error {
+	s.count++
+	return nil
+}
+
+// recordSink records the entries written to it.
+type recordSink struct {
+	mu      sync.Mutex
+	entries []*Entry
+}
+
+func (s *recordSink) WriteEntry(entry *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+// flushBuffer counts the number of times it is flushed.
+type flushBuffer struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (b *flushBuffer) Flush() error {
+	b.flushes++
+	return nil
+}
+
+func Test_SzLog_JSONSink(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	sink := NewJSONSink(buf)
+	chk.True(sink.Writer() == buf)
+
+	tm := time.Date(2023, 11, 19, 10, 20, 30, 5, time.UTC)
+	chk.NoErr(sink.WriteEntry(&Entry{
+		Time:    tm,
+		Level:   ErrorLevel,
+		Message: "line 1\nline 2 <&>",
+		Fields: []Field{
+			Int("n", 3),
+			Err(errors.New("bad")),
+			Duration("d", time.Second),
+			Any("list", []string{"a", "b"}),
+			Any("c", complex(1, 2)),
+		},
+	}))
+	chk.NoErr(sink.WriteEntry(&Entry{
+		Time:    tm,
+		Level:   DebugLevel,
+		Message: "no fields",
+	}))
+
+	chk.Str(buf.String(), ""+
+		`{"time":"2023-11-19T10:20:30.000000005Z","level":"error",`+
+		`"msg":"line 1\nline 2 <&>","fields":{"n":3,"error":"bad",`+
+		`"d":"1s","list":["a","b"],"c":"(1+2i)"}}`+"\n"+
+		`{"time":"2023-11-19T10:20:30.000000005Z","level":"debug",`+
+		`"msg":"no fields"}`+"\n",
+	)
+}
+
+func Test_SzLog_TextAndJSONSinks(t *testing.T) {
+	chk := szTest.CaptureLog(t)
+	defer chk.Release()
+
+	jsonBuf := new(bytes.Buffer)
+	logger := New(InfoLevel, log.Default())
+	chk.NoErr(logger.AddJSONWriter(jsonBuf))
+
+	logger.Infow("user login", "user", "bob")
+	logger.Error("failed\nbadly")
+
+	chk.Str(normJSON(jsonBuf.String()), ""+
+		`{"time":"T","level":"info","msg":"user login",`+
+		`"fields":{"user":"bob"}}`+"\n"+
+		`{"time":"T","level":"error","msg":"failed\nbadly"}`+"\n",
+	)
+
+	chk.Log("" +
+		"I: user login user=bob\n" +
+		"E: failed\n" +
+		"+  badly\n" +
+		"")
+}
+
+func Test_SzLog_AddSinkDuplicates(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	logLogger := log.New(buf, "", 0)
+	jsonSink := NewJSONSink(new(bytes.Buffer))
+
+	logger := New(ErrorLevel, logLogger)
+	chk.Err(logger.AddSink(NewTextSink(logLogger)), "duplicate logger added")
+	chk.Err(logger.AddJSONWriter(buf), "duplicate os.Writer added")
+	chk.NoErr(logger.AddSink(jsonSink))
+	chk.Err(logger.AddSink(jsonSink), "duplicate sink added")
+}
+
+func Test_SzLog_CallerString(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	chk.Str(Caller{}.String(), "")
+	chk.Str(Caller{File: "file.go", Line: 1}.String(), "file.go:1")
+	chk.Str(Caller{File: "dir/file.go", Line: 2}.String(), "dir/file.go:2")
+	chk.Str(Caller{File: "/a/b/dir/file.go", Line: 3}.String(), "dir/file.go:3")
+}
+
+func Test_SzLog_TextSinkFileFlags(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	caller := Caller{File: "/src/dir/file.go", Line: 42}
+
+	for _, tst := range []struct {
+		prefix string
+		flags  int
+		want   string
+	}{
+		{"", 0, "E: msg\n"},
+		{"pre: ", log.Lshortfile, "pre: file.go:42: E: msg\n"},
+		{"pre: ", log.Llongfile, "pre: /src/dir/file.go:42: E: msg\n"},
+		{
+			"pre: ", log.Lshortfile | log.Lmsgprefix,
+			"file.go:42: pre: E: msg\n",
+		},
+	} {
+		buf := new(bytes.Buffer)
+		sink := NewTextSink(log.New(buf, tst.prefix, tst.flags))
+		chk.NoErr(sink.WriteEntry(&Entry{
+			Level:   ErrorLevel,
+			Message: "msg",
+			Caller:  caller,
+		}))
+		chk.Str(buf.String(), tst.want)
+	}
+
+	// The log.Logger used for callers not on the stack is reused.
+	buf := new(bytes.Buffer)
+	sink := NewTextSink(log.New(buf, "", log.Lshortfile))
+	entry := &Entry{Level: ErrorLevel, Message: "msg", Caller: caller}
+	chk.NoErr(sink.WriteEntry(entry))
+	located := sink.located
+	chk.NoErr(sink.WriteEntry(entry))
+	chk.True(located != nil && located == sink.located)
+	chk.Str(buf.String(), "file.go:42: E: msg\nfile.go:42: E: msg\n")
+
+	buf.Reset()
+	chk.NoErr(sink.WriteEntry(&Entry{Level: ErrorLevel, Message: "unknown"}))
+	chk.True(bytes.HasSuffix(buf.Bytes(), []byte(": E: unknown\n")))
+}
@@ -0,0 +1,179 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+)
+
+// SlogHandler is a slog.Handler writing records through a szLog.Logger.
+// Slog levels are mapped to the nearest szLog level at or below them and
+// attributes become fields with group names joined to keys by a period.
+type SlogHandler struct {
+	logger *Logger
+	group  string // Prefix added to keys: group names each followed by ".".
+}
+
+// NewSlogHandler returns a slog.Handler writing through the provided
+// szLog.Logger.  Use slog.New(szLog.NewSlogHandler(szLog.Default())) to
+// route slog output through the standard szLog.Logger.
+func NewSlogHandler(logger *Logger) *SlogHandler {
+	return &SlogHandler{logger: logger}
+}
+
+// FromSlogLevel maps a slog.Level to the szLog level at or below it.
+func FromSlogLevel(level slog.Level) Level {
+	switch {
+	case level < slog.LevelDebug:
+		return TraceLevel
+	case level < slog.LevelInfo:
+		return DebugLevel
+	case level < slog.LevelWarn:
+		return InfoLevel
+	case level < slog.LevelError:
+		return WarnLevel
+	default:
+		return ErrorLevel
+	}
+}
+
+// ToSlogLevel maps a szLog level to a slog.Level.  TraceLevel is four below
+// slog.LevelDebug while FatalLevel and PanicLevel are four and eight above
+// slog.LevelError respectively.
+func ToSlogLevel(level Level) slog.Level {
+	const step = 4
+
+	switch level {
+	case TraceLevel:
+		return slog.LevelDebug - step
+	case DebugLevel:
+		return slog.LevelDebug
+	case InfoLevel:
+		return slog.LevelInfo
+	case WarnLevel:
+		return slog.LevelWarn
+	case FatalLevel:
+		return slog.LevelError + step
+	case PanicLevel:
+		return slog.LevelError + 2*step
+	default:
+		return slog.LevelError
+	}
+}
+
+// Enabled implements slog.Handler.
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.Enabled(FromSlogLevel(level))
+}
+
+// Handle implements slog.Handler writing the record as an entry.  Any
+// fields carried by the context precede the record's attributes.
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	ctxFields := ContextFields(ctx)
+	entry := &Entry{
+		Time:    r.Time,
+		Level:   FromSlogLevel(r.Level),
+		Message: r.Message,
+		Fields:  make([]Field, 0, len(ctxFields)+r.NumAttrs()),
+	}
+	entry.Fields = append(entry.Fields, ctxFields...)
+	r.Attrs(func(a slog.Attr) bool {
+		entry.Fields = appendAttr(entry.Fields, h.group, a)
+		return true
+	})
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		entry.Caller = Caller{PC: r.PC, File: frame.File, Line: frame.Line}
+	}
+	h.logger.write(entry)
+	return nil
+}
+
+// WithAttrs implements slog.Handler returning a handler writing through a
+// child szLog.Logger bound to the attributes.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make([]any, 0, len(attrs))
+	for _, a := range attrs {
+		for _, f := range appendAttr(nil, h.group, a) {
+			fields = append(fields, f)
+		}
+	}
+	return &SlogHandler{logger: h.logger.With(fields...), group: h.group}
+}
+
+// WithGroup implements slog.Handler.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &SlogHandler{logger: h.logger, group: h.group + name + "."}
+}
+
+// appendAttr appends the attribute to fields flattening groups into keys
+// joined by periods.  Empty attributes are ignored and groups without a key
+// are inlined as slog.Handler requires.
+func appendAttr(fields []Field, group string, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			group += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			fields = appendAttr(fields, group, ga)
+		}
+		return fields
+	}
+	return append(fields, Field{Key: group + a.Key, Value: a.Value.Any()})
+}
+
+// SlogSink is a Sink writing entries to a slog.Handler.  It must not wrap a
+// SlogHandler writing back to the same szLog.Logger.
+type SlogSink struct {
+	handler slog.Handler
+}
+
+// NewSlogSink creates a SlogSink writing to the provided slog.Handler.
+func NewSlogSink(handler slog.Handler) *SlogSink {
+	return &SlogSink{handler: handler}
+}
+
+// Handler returns the slog.Handler written to by the SlogSink.
+func (s *SlogSink) Handler() slog.Handler {
+	return s.handler
+}
+
+// WriteEntry writes the entry as a slog.Record with its fields as
+// attributes if the handler is enabled for the entry's level.
+func (s *SlogSink) WriteEntry(entry *Entry) error {
+	ctx := context.Background()
+	level := ToSlogLevel(entry.Level)
+	if !s.handler.Enabled(ctx, level) {
+		return nil
+	}
+	r := slog.NewRecord(entry.Time, level, entry.Message, entry.Caller.PC)
+	for _, f := range entry.Fields {
+		r.AddAttrs(slog.Any(f.Key, f.Value))
+	}
+	return s.handler.Handle(ctx, r)
+}
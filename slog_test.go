@@ -0,0 +1,159 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dancsecs/szTest"
+)
+
+func Test_SzLog_SlogLevels(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	chk.Int(int(FromSlogLevel(slog.LevelDebug-1)), int(TraceLevel))
+	chk.Int(int(FromSlogLevel(slog.LevelDebug)), int(DebugLevel))
+	chk.Int(int(FromSlogLevel(slog.LevelInfo+1)), int(InfoLevel))
+	chk.Int(int(FromSlogLevel(slog.LevelWarn)), int(WarnLevel))
+	chk.Int(int(FromSlogLevel(slog.LevelError)), int(ErrorLevel))
+	chk.Int(int(FromSlogLevel(slog.LevelError+8)), int(ErrorLevel))
+
+	chk.Str(ToSlogLevel(TraceLevel).String(), "DEBUG-4")
+	chk.Str(ToSlogLevel(DebugLevel).String(), "DEBUG")
+	chk.Str(ToSlogLevel(InfoLevel).String(), "INFO")
+	chk.Str(ToSlogLevel(WarnLevel).String(), "WARN")
+	chk.Str(ToSlogLevel(ErrorLevel).String(), "ERROR")
+	chk.Str(ToSlogLevel(FatalLevel).String(), "ERROR+4")
+	chk.Str(ToSlogLevel(PanicLevel).String(), "ERROR+8")
+}
+
+func Test_SzLog_SlogHandler(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	logger := New(InfoLevel, log.New(io.Discard, "", 0))
+	chk.NoErr(logger.AddJSONWriter(buf))
+
+	sl := slog.New(NewSlogHandler(logger))
+	chk.False(sl.Enabled(context.Background(), slog.LevelDebug))
+	chk.True(sl.Enabled(context.Background(), slog.LevelInfo))
+
+	sl.Debug("not logged")
+	sl.Info("plain")
+	sl.Warn("attrs", "n", 1, slog.Group("g", "a", "b"), slog.Attr{})
+	sl.With("k", "v").WithGroup("req").With("id", 7).Error(
+		"grouped", "err", errors.New("bad"),
+		slog.Group("", "inline", true),
+	)
+	sl.WithGroup("").WithGroup("x").Info("empty group")
+
+	chk.Str(normJSON(buf.String()), ""+
+		`{"time":"T","level":"info","msg":"plain"}`+"\n"+
+		`{"time":"T","level":"warn","msg":"attrs",`+
+		`"fields":{"n":1,"g.a":"b"}}`+"\n"+
+		`{"time":"T","level":"error","msg":"grouped",`+
+		`"fields":{"k":"v","req.id":7,"req.err":"bad",`+
+		`"req.inline":true}}`+"\n"+
+		`{"time":"T","level":"info","msg":"empty group"}`+"\n",
+	)
+}
+
+func Test_SzLog_SlogHandlerCaller(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	rec := new(recordSink)
+	logger := New(TraceLevel, log.New(io.Discard, "", 0))
+	chk.NoErr(logger.AddSink(rec))
+
+	sl := slog.New(NewSlogHandler(logger))
+	line := nextLine()
+	sl.Debug("debug", "d", time.Second)
+
+	chk.Int(len(rec.entries), 1)
+	e := rec.entries[0]
+	chk.Int(int(e.Level), int(DebugLevel))
+	chk.Str(e.Message, "debug")
+	chk.Str(e.Fields[0].String(), "d=1s")
+	chk.True(!e.Time.IsZero())
+	chk.Str(e.Caller.File[strings.LastIndexByte(e.Caller.File, '/')+1:],
+		"slog_test.go",
+	)
+	chk.Int(e.Caller.Line, line)
+}
+
+func Test_SzLog_SlogSink(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	handler := slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	sink := NewSlogSink(handler)
+	chk.True(sink.Handler() == handler)
+
+	logger := New(TraceLevel, log.New(io.Discard, "", 0))
+	chk.NoErr(logger.AddSink(sink))
+
+	logger.Trace("not enabled")
+	logger.Debug("debug")
+	logger.With("k", "v").Infow("info", "n", 2)
+	logger.Error("multi\nline")
+
+	chk.Str(buf.String(), ""+
+		"level=DEBUG msg=debug\n"+
+		"level=INFO msg=info k=v n=2\n"+
+		"level=ERROR msg=\"multi\\nline\"\n",
+	)
+}
+
+func Test_SzLog_SlogHandlerContext(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	logger := New(InfoLevel, log.New(buf, "", 0))
+	sl := slog.New(NewSlogHandler(logger))
+
+	ctx := ContextWithRequestID(context.Background(), "r1")
+	sl.InfoContext(ctx, "handled", "n", 1)
+	sl.Info("no context")
+
+	chk.Str(buf.String(), ""+
+		"I: handled request_id=r1 n=1\n"+
+		"I: no context\n",
+	)
+}
@@ -0,0 +1,176 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"io"
+	"log"
+	"runtime"
+	"strings"
+)
+
+// stdLogDepth is the call depth from emit to the caller of a log.Logger
+// writing to a levelWriter: emit, levelWriter.Write, log.Logger's internal
+// output function and its Print, Printf or Println function.
+const stdLogDepth = callerDepth + 1
+
+// levelWriter is an io.Writer logging each write as a message at a fixed
+// level.
+type levelWriter struct {
+	logger *Logger
+	level  Level
+}
+
+// Write logs p, less any trailing newline, as a single message.  It never
+// returns an error.  Writes made by the log package's functions, such as
+// log.Print, after log.SetOutput are not written to sinks writing through
+// log.Default() as they would reenter it.  A write made while logging an
+// earlier write to a levelWriter would loop so is dropped.
+func (w *levelWriter) Write(p []byte) (int, error) {
+	if !w.logger.Enabled(w.level) {
+		return len(p), nil
+	}
+	stdLog, loop := writeCallers()
+	if loop {
+		return len(p), nil
+	}
+	msg := strings.TrimSuffix(string(p), "\n")
+	entry := w.logger.newEntry(stdLogDepth, w.level, msg, nil)
+	if stdLog {
+		entry.source = log.Default()
+	}
+	w.logger.write(entry)
+	return len(p), nil
+}
+
+// writeCallers examines the callers of levelWriter.Write returning whether
+// it was called by one of the log package's functions writing to
+// log.Default() and whether it was called while logging an earlier write to
+// a levelWriter.
+func writeCallers() (stdLog, loop bool) {
+	const maxFrames = 64
+	pcs := make([]uintptr, maxFrames)
+	// Skip runtime.Callers and writeCallers.
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	write, more := frames.Next()
+	first := true
+	for more {
+		var frame runtime.Frame
+		frame, more = frames.Next()
+		if frame.Function == write.Function {
+			return stdLog, true
+		}
+		if first && !strings.HasPrefix(frame.Function, "log.(*Logger).") {
+			stdLog = strings.HasPrefix(frame.Function, "log.")
+			first = false
+		}
+	}
+	return stdLog, false
+}
+
+// feedsBack returns true if the sink writes to an io.Writer logging to the
+// same underlying szLog.Logger which would otherwise loop endlessly.
+func (logger *Logger) feedsBack(s Sink) bool {
+	ws, ok := s.(writerSink)
+	if !ok {
+		return false
+	}
+	w, ok := ws.Writer().(*levelWriter)
+	return ok && w.logger.core() == logger.core()
+}
+
+// Writer returns an io.Writer logging each write through the selected
+// szLog.Logger as a message at the level.  Sinks writing to the returned
+// io.Writer cannot be added to the same szLog.Logger as they would loop.
+func (logger *Logger) Writer(level Level) io.Writer {
+	return &levelWriter{logger: logger, level: level}
+}
+
+// Writer returns an io.Writer logging each write through the standard
+// szLog.Logger as a message at the level.  Sinks writing to the returned
+// io.Writer cannot be added to the same szLog.Logger as they would loop.
+// It may be passed to log.SetOutput though RedirectStdLog also keeps any sink
+// writing through log.Default() working.
+func Writer(level Level) io.Writer {
+	return std.Writer(level)
+}
+
+// StdLogger returns a log.Logger, suitable for http.Server.ErrorLog and
+// similar, whose output is logged through the selected szLog.Logger at the
+// level.  The caller of the log.Logger is recorded as the entry's caller.
+func (logger *Logger) StdLogger(level Level) *log.Logger {
+	return log.New(logger.Writer(level), "", 0)
+}
+
+// StdLogger returns a log.Logger, suitable for http.Server.ErrorLog and
+// similar, whose output is logged through the standard szLog.Logger at the
+// level.  The caller of the log.Logger is recorded as the entry's caller.
+func StdLogger(level Level) *log.Logger {
+	return std.StdLogger(level)
+}
+
+// RedirectStdLog redirects the log package's standard logger through the
+// selected szLog.Logger at the level.  Any sink writing to log.Default() is
+// first replaced by one writing to its current output with the same prefix
+// and flags so messages continue to appear there rather than looping.  The
+// returned function restores the log package and the replaced sink.
+func (logger *Logger) RedirectStdLog(level Level) (restore func()) {
+	out, prefix, flags := log.Writer(), log.Prefix(), log.Flags()
+	replacement := log.New(out, prefix, flags)
+	replaced := logger.replaceLogLogger(log.Default(), replacement) == nil
+
+	log.SetOutput(logger.Writer(level))
+	log.SetPrefix("")
+	log.SetFlags(0)
+
+	return func() {
+		log.SetOutput(out)
+		log.SetPrefix(prefix)
+		log.SetFlags(flags)
+		if replaced {
+			_ = logger.replaceLogLogger(replacement, log.Default())
+		}
+	}
+}
+
+// RedirectStdLog redirects the log package's standard logger through the
+// standard szLog.Logger at the level.  Any sink writing to log.Default() is
+// first replaced by one writing to its current output with the same prefix
+// and flags so messages continue to appear there rather than looping.  The
+// returned function restores the log package and the replaced sink.
+func RedirectStdLog(level Level) (restore func()) {
+	return std.RedirectStdLog(level)
+}
+
+// replaceLogLogger replaces the sink writing through the old log.Logger with
+// an equivalent one writing through the new log.Logger.
+func (logger *Logger) replaceLogLogger(
+	oldLogger, newLogger *log.Logger,
+) error {
+	return logger.replaceSink(
+		"logger not found",
+		func(s Sink) bool {
+			l, ok := s.(loggerSink)
+			return ok && l.Logger() == oldLogger
+		},
+		func(s Sink) (Sink, error) {
+			return s.(loggerSink).withLogger(newLogger), nil
+		},
+	)
+}
@@ -0,0 +1,143 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/dancsecs/szTest"
+)
+
+func Test_SzLog_StdLogger(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	rec := new(recordSink)
+	logger := New(WarnLevel, log.New(buf, "", 0))
+	chk.NoErr(logger.AddSink(rec))
+
+	stdLogger := logger.StdLogger(WarnLevel)
+	line := nextLine()
+	stdLogger.Printf("from %s", "log")
+	logger.StdLogger(InfoLevel).Print("filtered")
+	_, err := fmt.Fprintln(logger.Writer(ErrorLevel), "line 1\nline 2")
+	chk.NoErr(err)
+
+	chk.Str(buf.String(), ""+
+		"W: from log\n"+
+		"E: line 1\n"+
+		"+  line 2\n",
+	)
+
+	chk.Int(len(rec.entries), 2)
+	e := rec.entries[0]
+	chk.Str(e.Caller.File[strings.LastIndexByte(e.Caller.File, '/')+1:],
+		"stdlog_test.go",
+	)
+	chk.Int(e.Caller.Line, line)
+}
+
+func Test_SzLog_WriterLoop(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	logger := New(InfoLevel, log.New(buf, "", 0))
+	child := logger.With("k", "v")
+
+	chk.Err(
+		logger.AddWriter(child.Writer(InfoLevel), "", 0),
+		"sink writes back to logger",
+	)
+	chk.Err(
+		child.AddLogger(logger.StdLogger(WarnLevel)),
+		"sink writes back to logger",
+	)
+	chk.Err(
+		logger.AddJSONWriterAt(TraceLevel, logger.Writer(ErrorLevel)),
+		"sink writes back to logger",
+	)
+	chk.Err(
+		logger.ReplaceWriter(buf, logger.Writer(InfoLevel)),
+		"sink writes back to logger",
+	)
+
+	other := New(InfoLevel, log.New(io.Discard, "", 0))
+	chk.NoErr(logger.AddWriter(other.Writer(InfoLevel), "", 0))
+}
+
+func Test_SzLog_Default_RedirectStdLog(t *testing.T) {
+	chk := szTest.CaptureLog(t)
+	defer chk.Release()
+
+	SetLevel(InfoLevel)
+	defer SetLevel(ErrorLevel)
+
+	log.SetPrefix("pre:")
+	defer log.SetPrefix("")
+
+	restore := RedirectStdLog(WarnLevel)
+	log.Print("redirected")
+	Info("native")
+	StdLogger(ErrorLevel).Print("adapter")
+	_, err := io.WriteString(Writer(TraceLevel), "filtered")
+	chk.NoErr(err)
+	restore()
+
+	log.Print("restored")
+	Info("native")
+
+	chk.Log("" +
+		"pre:W: redirected\n" +
+		"pre:I: native\n" +
+		"pre:E: adapter\n" +
+		"pre:restored\n" +
+		"pre:I: native\n" +
+		"")
+}
+
+func Test_SzLog_Default_SetOutput(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	chk.NoErr(AddWriter(buf, "", 0))
+	defer func() { chk.NoErr(RemoveWriter(buf)) }()
+
+	origWriter, origFlags := log.Writer(), log.Flags()
+	log.SetOutput(Writer(ErrorLevel))
+	log.SetFlags(0)
+	log.Print("print")
+	log.Printf("printf %d", 1)
+	StdLogger(ErrorLevel).Print("adapter")
+	log.SetOutput(origWriter)
+	log.SetFlags(origFlags)
+
+	chk.Str(buf.String(), ""+
+		"E: print\n"+
+		"E: printf 1\n"+
+		"E: adapter\n",
+	)
+}
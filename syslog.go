@@ -0,0 +1,349 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SyslogFormat selects the syslog message format.
+type SyslogFormat int
+
+// Defines the SyslogFormats.
+const (
+	// RFC5424 formats messages as defined by RFC 5424 with fields sent as
+	// structured data.
+	RFC5424 SyslogFormat = iota
+	// RFC3164 formats messages in the traditional BSD form with fields
+	// following the message as key=value pairs.
+	RFC3164
+)
+
+// Facility is a syslog facility code.
+type Facility int
+
+// Defines the syslog facilities.
+const (
+	FacilityKern Facility = iota
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLpr
+	FacilityNews
+	FacilityUucp
+	FacilityCron
+	FacilityAuthPriv
+	FacilityFtp
+	_
+	_
+	_
+	_
+	FacilityLocal0
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+// Syslog severities.
+const (
+	severityAlert   = 1
+	severityCrit    = 2
+	severityErr     = 3
+	severityWarning = 4
+	severityInfo    = 6
+	severityDebug   = 7
+)
+
+// syslogSeverity maps the level to a syslog severity.
+func syslogSeverity(level Level) int {
+	switch level {
+	case PanicLevel:
+		return severityAlert
+	case FatalLevel:
+		return severityCrit
+	case ErrorLevel:
+		return severityErr
+	case WarnLevel:
+		return severityWarning
+	case InfoLevel:
+		return severityInfo
+	default:
+		return severityDebug
+	}
+}
+
+// defaultSDID is the structured data id used for fields when none is
+// provided.  32473 is the private enterprise number reserved for
+// documentation by RFC 5612.
+const defaultSDID = "fields@32473"
+
+// defaultSyslogTimeout limits each connection attempt and write when no
+// timeout is provided.
+const defaultSyslogTimeout = 5 * time.Second
+
+// localSyslogPaths are the Unix sockets searched for the local syslog
+// daemon.
+//
+//nolint:goCheckNoGlobals // ok
+var localSyslogPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogOptions defines where and how a SyslogSink sends messages.
+type SyslogOptions struct {
+	// Network is one of "unixgram", "unix" or a "udp" or "tcp" network as
+	// accepted by net.Dial.  When empty the local syslog daemon is located by
+	// searching the usual Unix sockets.
+	Network string
+
+	// Address is the socket path or host:port of the daemon.
+	Address string
+
+	// Format selects RFC 5424 (the default) or RFC 3164 messages.
+	Format SyslogFormat
+
+	// Facility is combined with each entry's severity to form its priority.
+	// Processes may not log as FacilityKern so it is replaced by
+	// FacilityUser.
+	Facility Facility
+
+	// Tag identifies the application.  It defaults to the program's name.
+	Tag string
+
+	// Hostname identifies the host.  It defaults to os.Hostname.
+	Hostname string
+
+	// SDID is the RFC 5424 structured data id under which fields are sent.
+	// It defaults to "fields@32473".
+	SDID string
+
+	// Timeout limits each connection attempt and write.  It defaults to 5s.
+	Timeout time.Duration
+}
+
+// SyslogSink is a Sink sending entries to a syslog daemon.  Messages sent
+// over tcp use octet counting framing as defined by RFC 6587 and those sent
+// over unix stream sockets are terminated by a newline as local daemons
+// expect.  A failed send is retried once over a new connection.  Connection
+// attempts and writes are limited by the options' Timeout.
+type SyslogSink struct {
+	opts SyslogOptions
+	pid  string
+
+	mu     sync.Mutex
+	conn   net.Conn
+	closed bool
+}
+
+// NewSyslogSink connects to the syslog daemon returning a SyslogSink sending
+// entries as defined by the options.
+func NewSyslogSink(opts SyslogOptions) (*SyslogSink, error) {
+	if opts.Facility == FacilityKern {
+		opts.Facility = FacilityUser
+	}
+	if opts.Tag == "" {
+		opts.Tag = filepath.Base(os.Args[0])
+	}
+	if opts.Hostname == "" {
+		opts.Hostname, _ = os.Hostname()
+	}
+	if opts.SDID == "" {
+		opts.SDID = defaultSDID
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultSyslogTimeout
+	}
+	s := &SyslogSink{opts: opts, pid: strconv.Itoa(os.Getpid())}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// connect dials the syslog daemon.  It must be called with mu held (or
+// before the SyslogSink is shared).
+func (s *SyslogSink) connect() error {
+	if s.opts.Network != "" {
+		conn, err := net.DialTimeout(
+			s.opts.Network, s.opts.Address, s.opts.Timeout,
+		)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+		return nil
+	}
+	for _, path := range localSyslogPaths {
+		for _, network := range []string{"unixgram", "unix"} {
+			conn, err := net.DialTimeout(network, path, s.opts.Timeout)
+			if err == nil {
+				s.opts.Network, s.opts.Address = network, path
+				s.conn = conn
+				return nil
+			}
+		}
+	}
+	return errors.New("syslog daemon not found")
+}
+
+// frame returns the message framed as required by the current connection.
+// It must be called with mu held.
+func (s *SyslogSink) frame(msg []byte) []byte {
+	switch {
+	case strings.HasPrefix(s.opts.Network, "tcp"):
+		return append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	case s.opts.Network == "unix":
+		return append(msg[:len(msg):len(msg)], '\n')
+	default:
+		return msg
+	}
+}
+
+// send frames and writes the message to the current connection within the
+// timeout.  It must be called with mu held.
+func (s *SyslogSink) send(msg []byte) error {
+	msg = s.frame(msg)
+	err := s.conn.SetWriteDeadline(time.Now().Add(s.opts.Timeout))
+	if err == nil {
+		_, err = s.conn.Write(msg)
+	}
+	return err
+}
+
+// WriteEntry sends the entry to the syslog daemon.
+func (s *SyslogSink) WriteEntry(entry *Entry) error {
+	msg := s.format(entry)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errors.New("syslog sink closed")
+	}
+	if s.conn != nil {
+		if err := s.send(msg); err == nil {
+			return nil
+		}
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	if err := s.connect(); err != nil {
+		return err
+	}
+	return s.send(msg)
+}
+
+// Close closes the connection to the syslog daemon.
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// format returns the entry as a syslog message in the selected format.
+func (s *SyslogSink) format(entry *Entry) []byte {
+	pri := int(s.opts.Facility)*8 + syslogSeverity(entry.Level)
+	var b strings.Builder
+	b.WriteString("<" + strconv.Itoa(pri) + ">")
+	if s.opts.Format == RFC3164 {
+		b.WriteString(entry.Time.Format(time.Stamp) + " ")
+		b.WriteString(s.opts.Hostname + " ")
+		b.WriteString(s.opts.Tag + "[" + s.pid + "]: ")
+		b.WriteString(entry.Message)
+		appendFields(&b, entry.Fields)
+		return []byte(b.String())
+	}
+	b.WriteString("1 ")
+	b.WriteString(entry.Time.Format("2006-01-02T15:04:05.000000Z07:00") + " ")
+	b.WriteString(sdHeader(s.opts.Hostname) + " ")
+	b.WriteString(sdHeader(s.opts.Tag) + " ")
+	b.WriteString(s.pid + " - ")
+	if len(entry.Fields) == 0 {
+		b.WriteString("-")
+	} else {
+		b.WriteString("[" + s.opts.SDID)
+		for _, f := range entry.Fields {
+			b.WriteString(" " + sdName(f.Key) + "=\"")
+			b.WriteString(sdValue.Replace(f.ValueString()) + "\"")
+		}
+		b.WriteString("]")
+	}
+	if entry.Message != "" {
+		b.WriteString(" " + entry.Message)
+	}
+	return []byte(b.String())
+}
+
+// sdValue escapes the characters RFC 5424 requires to be escaped in
+// structured data parameter values.
+//
+//nolint:goCheckNoGlobals // ok
+var sdValue = strings.NewReplacer(`"`, `\"`, `\`, `\\`, "]", `\]`)
+
+// sdName returns the key as a valid RFC 5424 parameter name: at most 32
+// printable ASCII characters other than '=', ' ', ']' and '"' which are
+// replaced by underscores.
+func sdName(key string) string {
+	const maxLen = 32
+	name := []byte(key)
+	if len(name) > maxLen {
+		name = name[:maxLen]
+	}
+	for i, c := range name {
+		if c <= ' ' || c > '~' || c == '=' || c == ']' || c == '"' {
+			name[i] = '_'
+		}
+	}
+	if len(name) == 0 {
+		return "_"
+	}
+	return string(name)
+}
+
+// sdHeader returns the value as a valid RFC 5424 header field: printable
+// ASCII without spaces or "-" if empty.
+func sdHeader(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return '_'
+		}
+		return r
+	}, value)
+}
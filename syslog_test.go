@@ -0,0 +1,235 @@
+/*
+   Szerszam Log Utility: szLog.
+   Copyright (C) 2023  Leslie Dancsecs
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package szLog
+
+import (
+	"bufio"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dancsecs/szTest"
+)
+
+// syslogTime is the fixed time of entries sent in syslog tests.
+//
+//nolint:goCheckNoGlobals // ok
+var syslogTime = time.Date(2023, 11, 19, 10, 20, 30, 123456000, time.UTC)
+
+// readPacket returns the next datagram received by the connection.
+func readPacket(chk *szTest.Chk, conn net.PacketConn) string {
+	buf := make([]byte, 4096)
+	chk.NoErr(conn.SetReadDeadline(time.Now().Add(5 * time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	chk.NoErr(err)
+	return string(buf[:n])
+}
+
+// readFrame returns the next octet counted message read from r.
+func readFrame(chk *szTest.Chk, r *bufio.Reader) string {
+	size, err := r.ReadString(' ')
+	chk.NoErr(err)
+	n, err := strconv.Atoi(strings.TrimSuffix(size, " "))
+	chk.NoErr(err)
+	buf := make([]byte, n)
+	_, err = io.ReadFull(r, buf)
+	chk.NoErr(err)
+	return string(buf)
+}
+
+func Test_SzLog_SyslogUnixgram(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	path := filepath.Join(chk.CreateTmpDir(), "log.sock")
+	server, err := net.ListenPacket("unixgram", path)
+	chk.NoErr(err)
+	defer func() { chk.NoErr(server.Close()) }()
+
+	sink, err := NewSyslogSink(SyslogOptions{
+		Network:  "unixgram",
+		Address:  path,
+		Facility: FacilityLocal0,
+		Tag:      "app",
+		Hostname: "host",
+	})
+	chk.NoErr(err)
+
+	pid := strconv.Itoa(os.Getpid())
+	chk.NoErr(sink.WriteEntry(&Entry{
+		Time:    syslogTime,
+		Level:   ErrorLevel,
+		Message: "failed",
+		Fields: []Field{
+			Int("n", 1),
+			String("quote", `a"b\c]d`),
+			String("bad key=", "v"),
+		},
+	}))
+	chk.Str(readPacket(chk, server), ""+
+		"<131>1 2023-11-19T10:20:30.123456Z host app "+pid+" - "+
+		`[fields@32473 n="1" quote="a\"b\\c\]d" bad_key_="v"] failed`,
+	)
+
+	chk.NoErr(sink.WriteEntry(&Entry{Time: syslogTime, Level: DebugLevel}))
+	chk.Str(readPacket(chk, server),
+		"<135>1 2023-11-19T10:20:30.123456Z host app "+pid+" - -",
+	)
+
+	chk.NoErr(sink.Close())
+	chk.Err(sink.WriteEntry(&Entry{}), "syslog sink closed")
+	chk.NoErr(sink.Close())
+}
+
+func Test_SzLog_SyslogUDP(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	chk.NoErr(err)
+	defer func() { chk.NoErr(server.Close()) }()
+
+	sink, err := NewSyslogSink(SyslogOptions{
+		Network:  "udp",
+		Address:  server.LocalAddr().String(),
+		Format:   RFC3164,
+		Facility: FacilityDaemon,
+		Tag:      "app",
+		Hostname: "host",
+	})
+	chk.NoErr(err)
+	defer func() { chk.NoErr(sink.Close()) }()
+
+	logger := New(TraceLevel, log.New(io.Discard, "", 0))
+	chk.NoErr(logger.AddSink(sink))
+	logger.SetExitFunc(func(int) {})
+
+	pid := strconv.Itoa(os.Getpid())
+	logger.Tracew("trace", "k", "v")
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+	logger.Fatal("fatal")
+	chk.Panic(func() { logger.Panic("panic") }, "panic")
+
+	for _, want := range []string{
+		"<31>S host app[" + pid + "]: trace k=v",
+		"<31>S host app[" + pid + "]: debug",
+		"<30>S host app[" + pid + "]: info",
+		"<28>S host app[" + pid + "]: warn",
+		"<27>S host app[" + pid + "]: error",
+		"<26>S host app[" + pid + "]: fatal",
+		"<25>S host app[" + pid + "]: panic",
+	} {
+		got := readPacket(chk, server)
+		i := strings.IndexByte(got, '>') + 1
+		_, err := time.Parse(time.Stamp, got[i:i+len(time.Stamp)])
+		chk.NoErr(err)
+		chk.Str(got[:i]+"S"+got[i+len(time.Stamp):], want)
+	}
+}
+
+func Test_SzLog_SyslogTCP(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	server, err := net.Listen("tcp4", "127.0.0.1:0")
+	chk.NoErr(err)
+	defer func() { chk.NoErr(server.Close()) }()
+
+	sink, err := NewSyslogSink(SyslogOptions{
+		Network: "tcp4",
+		Address: server.Addr().String(),
+		Tag:     "my app",
+	})
+	chk.NoErr(err)
+	defer func() { chk.NoErr(sink.Close()) }()
+
+	hostname, _ := os.Hostname()
+	pid := strconv.Itoa(os.Getpid())
+	prefix := "<14>1 2023-11-19T10:20:30.123456Z " + hostname +
+		" my_app " + pid + " - - "
+
+	conn, err := server.Accept()
+	chk.NoErr(err)
+	r := bufio.NewReader(conn)
+	chk.NoErr(sink.WriteEntry(&Entry{
+		Time: syslogTime, Level: InfoLevel, Message: "line 1\nline 2",
+	}))
+	chk.Str(readFrame(chk, r), prefix+"line 1\nline 2")
+
+	// A lost connection is reestablished.
+	chk.NoErr(conn.Close())
+	go func() {
+		for i := 0; i < 10; i++ {
+			_ = sink.WriteEntry(&Entry{
+				Time: syslogTime, Level: InfoLevel, Message: "again",
+			})
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+	conn, err = server.Accept()
+	chk.NoErr(err)
+	defer func() { chk.NoErr(conn.Close()) }()
+	chk.Str(readFrame(chk, bufio.NewReader(conn)), prefix+"again")
+}
+
+func Test_SzLog_SyslogLocal(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	dir := chk.CreateTmpDir()
+	path := filepath.Join(dir, "log")
+	lastPaths := localSyslogPaths
+	defer func() { localSyslogPaths = lastPaths }()
+
+	localSyslogPaths = []string{filepath.Join(dir, "missing")}
+	_, err := NewSyslogSink(SyslogOptions{})
+	chk.Err(err, "syslog daemon not found")
+
+	server, err := net.Listen("unix", path)
+	chk.NoErr(err)
+	defer func() { chk.NoErr(server.Close()) }()
+
+	localSyslogPaths = append(localSyslogPaths, path)
+	sink, err := NewSyslogSink(SyslogOptions{Hostname: "h", Tag: "t"})
+	chk.NoErr(err)
+	defer func() { chk.NoErr(sink.Close()) }()
+
+	conn, err := server.Accept()
+	chk.NoErr(err)
+	defer func() { chk.NoErr(conn.Close()) }()
+
+	chk.NoErr(sink.WriteEntry(&Entry{
+		Time: syslogTime, Level: WarnLevel, Message: "local",
+	}))
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	chk.NoErr(err)
+	chk.Str(line,
+		"<12>1 2023-11-19T10:20:30.123456Z h t "+
+			strconv.Itoa(os.Getpid())+" - - local\n",
+	)
+}
@@ -17,20 +17,29 @@
 */
 
 /*
-Package szLog provides for writing to logs with four levels of detail as
+Package szLog provides for writing to logs with five levels of detail as
 follows:
 
+- Trace
 - Debug
 - Info
 - Warn
 - Error
 
+with Fatal and Panic messages logged, like Error, regardless of the level
+before exiting or panicking respectively.
+
 It layers on top of the standard golang log package following its design lead
 providing for both a default (standard) logger that can be directly accesses
 with package level functions and variables or can create an independent
 logging object to be used by applications.  Multiple log.Loggers can be added
 as long as they reference different underlying io.Writer objects and each can
-have its own flags.
+have its own flags.  All szLog.Loggers, including the standard one, are safe
+for concurrent use.
+
+Structured key/value fields may accompany a message using the Tracew, Debugw,
+Infow, Warnw and Errorw variants which render them as key=value after the
+message.
 */
 //nolint:goCheckNoGlobals,goCheckNoInits // ok
 package szLog
@@ -40,35 +49,49 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"strings"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
-// Level stores the current level of permitted logging.
-type Level uint32
+// core holds the state shared by a szLog.Logger and all of the children
+// created from it with With.  The level is held atomically and the list of
+// sinks is replaced (copy on write) whenever it changes so that logging never
+// takes a lock.
+type core struct {
+	level  uint32       // Accessed atomically.
+	active uint32       // Most verbose sink level.  Accessed atomically.
+	mu     sync.Mutex   // Serializes changes to level and logs.
+	logs   atomic.Value // Holds an immutable []levelSink.
+	exit   atomic.Value // Holds the func(int) called by Fatal.
+	format atomic.Value // Holds the formatterValue used by text sinks.
+}
 
-// Defines the various logging levels.
-const (
-	ErrorLevel Level = iota
-	WarnLevel
-	InfoLevel
-	DebugLevel
-)
+// levelSink pairs a sink with its own logging level.  Sinks added without a
+// level follow the level of the szLog.Logger.
+type levelSink struct {
+	Sink
+	level    Level
+	hasLevel bool
+}
 
-const (
-	debugLabel    = "D: "
-	infoLabel     = "I: "
-	warnLabel     = "W: "
-	errorLabel    = "E: "
-	continueLabel = "+  "
-)
+// levelFor returns the logging level the sink is currently filtering at.
+func (s levelSink) levelFor(loggerLevel Level) Level {
+	if s.hasLevel {
+		return s.level
+	}
+	return loggerLevel
+}
 
-// Logger represents a szLog logging object.
+// Logger represents a szLog logging object.  It is safe for concurrent use.
+// The zero value is ready to use logging errors to no sinks until added.
 type Logger struct {
-	level   Level
-	logs    []*log.Logger
-	IsDebug bool
-	IsInfo  bool
-	IsWarn  bool
+	shared     atomic.Pointer[core] // Created when first used.
+	fields     []Field              // Bound to every message by With.
+	callerSkip int                  // Extra frames skipped locating the caller.
+	recorder   *flightRecorder      // Set by WithFlightRecorder.
 }
 
 // New creats a new szLog.Logger with the provided logging level.
@@ -79,30 +102,208 @@ func New(level Level, logLogger *log.Logger) *Logger {
 	return logger
 }
 
-// Output writes the labeled stecified message to all szLog.Loggers added.
-func (logger *Logger) output(label, msg string) {
-	r := label
-	for i, l := range strings.Split(msg, "\n") {
-		if i > 0 {
-			r += "\n" + continueLabel
+// core returns the state shared by the Logger and its children creating it
+// should the Logger be a zero value.
+func (logger *Logger) core() *core {
+	if c := logger.shared.Load(); c != nil {
+		return c
+	}
+	logger.shared.CompareAndSwap(nil, new(core))
+	return logger.shared.Load()
+}
+
+// child returns a new Logger sharing the core of the Logger.
+func (logger *Logger) child(
+	fields []Field, callerSkip int, recorder *flightRecorder,
+) *Logger {
+	c := &Logger{fields: fields, callerSkip: callerSkip, recorder: recorder}
+	c.shared.Store(logger.core())
+	return c
+}
+
+// With returns a child szLog.Logger that adds the provided fields (or
+// alternating keys and values) to every message it writes.  The child shares
+// the level, logs and any flight recorder of its parent so changes made
+// through either are seen by both.
+func (logger *Logger) With(keysAndValues ...any) *Logger {
+	fields := fieldsFrom(keysAndValues)
+	bound := make([]Field, 0, len(logger.fields)+len(fields))
+	bound = append(append(bound, logger.fields...), fields...)
+	return logger.child(bound, logger.callerSkip, logger.recorder)
+}
+
+// WithCallerSkip returns a child szLog.Logger that skips the provided number
+// of additional stack frames when determining the caller of each message.
+// It allows helper functions wrapping a szLog.Logger to report their own
+// callers.  The child shares the level, logs, fields and any flight recorder
+// of its parent.
+func (logger *Logger) WithCallerSkip(skip int) *Logger {
+	return logger.child(
+		logger.fields, logger.callerSkip+skip, logger.recorder,
+	)
+}
+
+// callerDepth is the number of stack frames between emit and the code
+// calling the public logging function: emit itself, the unexported helper
+// (output, fatal, panic or close) and the public function.
+const callerDepth = 3
+
+// Output writes the stecified message and any fields at the provided level
+// to all sinks added.
+func (logger *Logger) output(level Level, msg string, fields []Field) {
+	logger.emit(callerDepth, level, msg, fields)
+}
+
+// emit builds the entry, locating the caller by skipping the provided number
+// of stack frames, and writes it to all sinks permitting its level.
+func (logger *Logger) emit(
+	depth int, level Level, msg string, fields []Field,
+) {
+	logger.write(logger.newEntry(depth+1, level, msg, fields))
+}
+
+// newEntry returns an entry for the message whose caller is depth frames
+// above newEntry.
+func (logger *Logger) newEntry(
+	depth int, level Level, msg string, fields []Field,
+) *Entry {
+	entry := &Entry{
+		Time:    time.Now(),
+		Level:   level,
+		Message: msg,
+		Fields:  fields,
+	}
+	if pc, file, line, ok := runtime.Caller(depth + logger.callerSkip); ok {
+		entry.Caller = Caller{PC: pc, File: file, Line: line}
+	}
+	return entry
+}
+
+// write adds any bound fields to the entry and writes it to all sinks
+// permitting its level.  With a flight recorder, entries rejected by any
+// sink are recorded and recorded entries are written to the sinks that
+// rejected them ahead of any error.  Sinks writing through the entry's
+// source log.Logger are skipped as they would write back into it.
+func (logger *Logger) write(entry *Entry) {
+	if n := len(logger.fields); n > 0 {
+		entry.Fields = append(logger.fields[:n:n], entry.Fields...)
+	}
+	entry.formatter = logger.Formatter()
+	sinks := logger.core().sinks()
+	if entry.source != nil {
+		sinks = withoutLogger(sinks, entry.source)
+	}
+	r := logger.recorder
+	if r != nil && entry.Level.permittedBy(ErrorLevel) {
+		r.replay(sinks)
+	}
+	var rejected []Sink
+	loggerLevel := logger.Level()
+	for _, s := range sinks {
+		if entry.Level.permittedBy(s.levelFor(loggerLevel)) {
+			_ = s.WriteEntry(entry)
+		} else if r != nil {
+			rejected = append(rejected, s.Sink)
 		}
-		r += l
 	}
-	for _, l := range logger.logs {
-		l.Print(r)
+	if len(rejected) > 0 {
+		r.record(entry, rejected)
 	}
 }
 
-// SetLevel sets the logging level for the Logger.
+// withoutLogger returns the sinks less any writing through the log.Logger.
+func withoutLogger(sinks []levelSink, logger *log.Logger) []levelSink {
+	kept := make([]levelSink, 0, len(sinks))
+	for _, s := range sinks {
+		if l, ok := s.Sink.(loggerSink); !ok || l.Logger() != logger {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+// sinks returns the current immutable list of sinks.
+func (c *core) sinks() []levelSink {
+	sinks, _ := c.logs.Load().([]levelSink)
+	return sinks
+}
+
+// updateActive recalculates the most verbose level of any sink.  It must be
+// called with mu held whenever the level or logs change.
+func (c *core) updateActive() {
+	loggerLevel := Level(atomic.LoadUint32(&c.level))
+	active := loggerLevel
+	for _, s := range c.sinks() {
+		if l := s.levelFor(loggerLevel); l > active {
+			active = l
+		}
+	}
+	atomic.StoreUint32(&c.active, uint32(active))
+}
+
+// SetLevel sets the logging level for the Logger returning the previous
+// level.  The level applies to all sinks added without their own level.
+// Levels above TraceLevel, such as FatalLevel, are not logging levels so are
+// ignored leaving the current level unchanged and returned.
 func (logger *Logger) SetLevel(newLevel Level) Level {
-	lastLevel := logger.level
-	logger.level = newLevel
-	logger.IsWarn = logger.level >= WarnLevel
-	logger.IsInfo = logger.level >= InfoLevel
-	logger.IsDebug = logger.level >= DebugLevel
+	c := logger.core()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if newLevel > TraceLevel {
+		return Level(atomic.LoadUint32(&c.level))
+	}
+	lastLevel := Level(atomic.SwapUint32(&c.level, uint32(newLevel)))
+	c.updateActive()
 	return lastLevel
 }
 
+// Level returns the current logging level for the Logger.
+func (logger *Logger) Level() Level {
+	return Level(atomic.LoadUint32(&logger.core().level))
+}
+
+// activeLevel returns the most verbose level permitted by any sink or, with
+// a flight recorder, TraceLevel as all messages are then recorded.
+func (logger *Logger) activeLevel() Level {
+	if logger.recorder != nil {
+		return TraceLevel
+	}
+	return logger.sinkLevel()
+}
+
+// sinkLevel returns the most verbose level permitted by any sink.
+func (logger *Logger) sinkLevel() Level {
+	return Level(atomic.LoadUint32(&logger.core().active))
+}
+
+// Enabled returns true if messages at the level are permitted by any sink
+// or would be recorded by a flight recorder.
+func (logger *Logger) Enabled(level Level) bool {
+	return level.permittedBy(logger.activeLevel())
+}
+
+// IsWarn returns true if warning level messages are enabled for any sink.
+func (logger *Logger) IsWarn() bool {
+	return logger.activeLevel() >= WarnLevel
+}
+
+// IsInfo returns true if information level messages are enabled for any
+// sink.
+func (logger *Logger) IsInfo() bool {
+	return logger.activeLevel() >= InfoLevel
+}
+
+// IsDebug returns true if debug level messages are enabled for any sink.
+func (logger *Logger) IsDebug() bool {
+	return logger.activeLevel() >= DebugLevel
+}
+
+// IsTrace returns true if trace level messages are enabled for any sink.
+func (logger *Logger) IsTrace() bool {
+	return logger.activeLevel() >= TraceLevel
+}
+
 // AddWriter wraps the provided io.Writer in a new log.Logger and adds it
 // to the logs output by the selected szLog.Logger.
 func (logger *Logger) AddWriter(
@@ -115,91 +316,413 @@ func (logger *Logger) AddWriter(
 // selected szLog.Logger.  Checks are made and an error is returned should
 // duplicate szLog.Loggers or duplicate underlying io.Writers be added.
 func (logger *Logger) AddLogger(newLogger *log.Logger) error {
-	for _, l := range logger.logs {
-		if l == newLogger {
-			return errors.New("duplicate logger added")
+	return logger.AddSink(NewTextSink(newLogger))
+}
+
+// AddJSONWriter adds a JSONSink writing to the provided io.Writer to the
+// logs output by the selected szLog.Logger.
+func (logger *Logger) AddJSONWriter(newWriter io.Writer) error {
+	return logger.AddSink(NewJSONSink(newWriter))
+}
+
+// AddSink adds the provided Sink to the logs output by the selected
+// szLog.Logger.  Checks are made and an error is returned should duplicate
+// sinks, szLog.Loggers or underlying io.Writers be added.
+func (logger *Logger) AddSink(newSink Sink) error {
+	return logger.addSink(levelSink{Sink: newSink})
+}
+
+// AddWriterAt is as AddWriter but the added log only receives messages
+// permitted by its own level rather than the szLog.Logger's level.
+func (logger *Logger) AddWriterAt(
+	level Level, newWriter io.Writer, prefix string, flags int,
+) error {
+	return logger.AddLoggerAt(level, log.New(newWriter, prefix, flags))
+}
+
+// AddLoggerAt is as AddLogger but the added log only receives messages
+// permitted by its own level rather than the szLog.Logger's level.
+func (logger *Logger) AddLoggerAt(level Level, newLogger *log.Logger) error {
+	return logger.AddSinkAt(level, NewTextSink(newLogger))
+}
+
+// AddJSONWriterAt is as AddJSONWriter but the added log only receives
+// messages permitted by its own level rather than the szLog.Logger's level.
+func (logger *Logger) AddJSONWriterAt(level Level, newWriter io.Writer) error {
+	return logger.AddSinkAt(level, NewJSONSink(newWriter))
+}
+
+// AddSinkAt is as AddSink but the added sink only receives messages
+// permitted by its own level rather than the szLog.Logger's level.  An error
+// is returned if the level is above TraceLevel.
+func (logger *Logger) AddSinkAt(level Level, newSink Sink) error {
+	if level > TraceLevel {
+		return fmt.Errorf("invalid level: %s", level)
+	}
+	return logger.addSink(levelSink{Sink: newSink, level: level, hasLevel: true})
+}
+
+// addSink adds the sink after checking that it is not a duplicate and does
+// not write back into the logger.
+func (logger *Logger) addSink(newSink levelSink) error {
+	c := logger.core()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if logger.feedsBack(newSink.Sink) {
+		return errors.New("sink writes back to logger")
+	}
+	sinks := c.sinks()
+	for _, s := range sinks {
+		if err := checkDuplicate(s.Sink, newSink.Sink); err != nil {
+			return err
+		}
+	}
+	newSinks := make([]levelSink, len(sinks), len(sinks)+1)
+	copy(newSinks, sinks)
+	c.logs.Store(append(newSinks, newSink))
+	c.updateActive()
+	return nil
+}
+
+// RemoveLogger removes the log.Logger previously added to the selected
+// szLog.Logger returning an error if it is not found.
+func (logger *Logger) RemoveLogger(oldLogger *log.Logger) error {
+	return logger.removeSinks("logger not found", func(s Sink) bool {
+		l, ok := s.(loggerSink)
+		return ok && l.Logger() == oldLogger
+	})
+}
+
+// RemoveWriter removes all sinks writing to the provided io.Writer from the
+// selected szLog.Logger returning an error if none are found.
+func (logger *Logger) RemoveWriter(oldWriter io.Writer) error {
+	return logger.removeSinks("writer not found", func(s Sink) bool {
+		w, ok := s.(writerSink)
+		return ok && w.Writer() == oldWriter
+	})
+}
+
+// RemoveSink removes the sink previously added to the selected szLog.Logger
+// returning an error if it is not found.
+func (logger *Logger) RemoveSink(oldSink Sink) error {
+	return logger.removeSinks("sink not found", func(s Sink) bool {
+		return s == oldSink
+	})
+}
+
+// removeSinks removes all sinks matched returning an error with the provided
+// message if none are.
+func (logger *Logger) removeSinks(
+	notFound string, match func(Sink) bool,
+) error {
+	c := logger.core()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sinks := c.sinks()
+	newSinks := make([]levelSink, 0, len(sinks))
+	for _, s := range sinks {
+		if !match(s.Sink) {
+			newSinks = append(newSinks, s)
+		}
+	}
+	if len(newSinks) == len(sinks) {
+		return errors.New(notFound)
+	}
+	c.logs.Store(newSinks)
+	c.updateActive()
+	return nil
+}
+
+// ReplaceWriter atomically replaces the sink writing to the old io.Writer
+// with an equivalent sink writing to the new io.Writer.  The replacement
+// keeps the sink's level and, for log.Loggers, its prefix and flags.  An
+// error is returned if the old writer is not found, the new writer is
+// already in use or the sink cannot change its writer.
+func (logger *Logger) ReplaceWriter(oldWriter, newWriter io.Writer) error {
+	return logger.replaceSink(
+		"writer not found",
+		func(s Sink) bool {
+			w, ok := s.(writerSink)
+			return ok && w.Writer() == oldWriter
+		},
+		func(s Sink) (Sink, error) {
+			r, ok := s.(replaceableSink)
+			if !ok {
+				return nil, errors.New("sink cannot replace its writer")
+			}
+			return r.withWriter(newWriter), nil
+		},
+	)
+}
+
+// replaceSink atomically replaces the first sink matched with the sink
+// returned by replace after checking that it does not duplicate any other
+// sink or write back into the logger.  An error with the provided message
+// is returned if no sink matches.
+func (logger *Logger) replaceSink(
+	notFound string,
+	match func(Sink) bool,
+	replace func(Sink) (Sink, error),
+) error {
+	c := logger.core()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sinks := c.sinks()
+	idx := -1
+	for i := 0; i < len(sinks) && idx < 0; i++ {
+		if match(sinks[i].Sink) {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return errors.New(notFound)
+	}
+	newSink, err := replace(sinks[idx].Sink)
+	if err != nil {
+		return err
+	}
+	if logger.feedsBack(newSink) {
+		return errors.New("sink writes back to logger")
+	}
+	for i, s := range sinks {
+		if i == idx {
+			continue
 		}
-		if l.Writer() == newLogger.Writer() {
-			return errors.New("duplicate os.Writer added")
+		if err := checkDuplicate(s.Sink, newSink); err != nil {
+			return err
 		}
 	}
-	logger.logs = append(logger.logs, newLogger)
+	newSinks := make([]levelSink, len(sinks))
+	copy(newSinks, sinks)
+	newSinks[idx].Sink = newSink
+	c.logs.Store(newSinks)
 	return nil
 }
 
+// Flush flushes every sink implementing Flusher returning the first error
+// encountered.
+func (logger *Logger) Flush() error {
+	var firstErr error
+	for _, s := range logger.core().sinks() {
+		if f, ok := s.Sink.(Flusher); ok {
+			if err := f.Flush(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// SetExitFunc replaces the function called by Fatal after logging, returning
+// the previous function.  A nil function restores os.Exit.
+func (logger *Logger) SetExitFunc(exitFunc func(code int)) func(code int) {
+	if exitFunc == nil {
+		exitFunc = os.Exit
+	}
+	lastFunc, _ := logger.core().exit.Swap(exitFunc).(func(int))
+	if lastFunc == nil {
+		lastFunc = os.Exit
+	}
+	return lastFunc
+}
+
+// exitFunc returns the function called by Fatal.
+func (logger *Logger) exitFunc() func(code int) {
+	if exitFunc, ok := logger.core().exit.Load().(func(int)); ok {
+		return exitFunc
+	}
+	return os.Exit
+}
+
+// fatal logs the message, flushes all sinks and exits.
+func (logger *Logger) fatal(msg string, fields []Field) {
+	logger.emit(callerDepth, FatalLevel, msg, fields)
+	_ = logger.Flush()
+	logger.exitFunc()(1)
+}
+
+// panic logs the message, flushes all sinks and panics with the message.
+func (logger *Logger) panic(msg string, fields []Field) {
+	logger.emit(callerDepth, PanicLevel, msg, fields)
+	_ = logger.Flush()
+	panic(msg)
+}
+
+// Sinks returns the sinks currently added to the selected szLog.Logger.
+// Writers and log.Loggers are represented by their TextSink.
+func (logger *Logger) Sinks() []Sink {
+	sinks := logger.core().sinks()
+	result := make([]Sink, len(sinks))
+	for i, s := range sinks {
+		result[i] = s.Sink
+	}
+	return result
+}
+
+// Trace writes an unformatted information message to the selected
+// szLog.Logger if trace level messages are enabled.
+func (logger *Logger) Trace(msg ...any) {
+	if logger.IsTrace() {
+		logger.output(TraceLevel, fmt.Sprint(msg...), nil)
+	}
+}
+
+// Tracef writes a formatted information message to the selected szLog.Logger
+// if trace level messages are enabled.
+func (logger *Logger) Tracef(msgFmt string, msgArgs ...any) {
+	if logger.IsTrace() {
+		logger.output(TraceLevel, fmt.Sprintf(msgFmt, msgArgs...), nil)
+	}
+}
+
+// Tracew writes a message with alternating keys and values (or Fields) to the
+// selected szLog.Logger if trace level messages are enabled.
+func (logger *Logger) Tracew(msg string, keysAndValues ...any) {
+	if logger.IsTrace() {
+		logger.output(TraceLevel, msg, fieldsFrom(keysAndValues))
+	}
+}
+
 // Debug writes an unformatted information message to the selected
 // szLog.Logger if debug level messages are enabled.
 func (logger *Logger) Debug(msg ...any) {
-	if logger.IsDebug {
-		logger.output(debugLabel, fmt.Sprint(msg...))
+	if logger.IsDebug() {
+		logger.output(DebugLevel, fmt.Sprint(msg...), nil)
 	}
 }
 
 // Debugf writes a formatted information message to the selected szLog.Logger
 // if debug level messages are enabled.
 func (logger *Logger) Debugf(msgFmt string, msgArgs ...any) {
-	if logger.IsDebug {
-		logger.output(debugLabel, fmt.Sprintf(msgFmt, msgArgs...))
+	if logger.IsDebug() {
+		logger.output(DebugLevel, fmt.Sprintf(msgFmt, msgArgs...), nil)
+	}
+}
+
+// Debugw writes a message with alternating keys and values (or Fields) to the
+// selected szLog.Logger if debug level messages are enabled.
+func (logger *Logger) Debugw(msg string, keysAndValues ...any) {
+	if logger.IsDebug() {
+		logger.output(DebugLevel, msg, fieldsFrom(keysAndValues))
 	}
 }
 
 // Info writes an unformatted information message to the selected szLog.Logger
 // if iformation level messages are enabled.
 func (logger *Logger) Info(msg ...any) {
-	if logger.IsInfo {
-		logger.output(infoLabel, fmt.Sprint(msg...))
+	if logger.IsInfo() {
+		logger.output(InfoLevel, fmt.Sprint(msg...), nil)
 	}
 }
 
 // Infof writes a formatted information message to the selected szLog.Logger
 // if information level messages are enabled.
 func (logger *Logger) Infof(msgFmt string, msgArgs ...any) {
-	if logger.IsInfo {
-		logger.output(infoLabel, fmt.Sprintf(msgFmt, msgArgs...))
+	if logger.IsInfo() {
+		logger.output(InfoLevel, fmt.Sprintf(msgFmt, msgArgs...), nil)
+	}
+}
+
+// Infow writes a message with alternating keys and values (or Fields) to the
+// selected szLog.Logger if information level messages are enabled.
+func (logger *Logger) Infow(msg string, keysAndValues ...any) {
+	if logger.IsInfo() {
+		logger.output(InfoLevel, msg, fieldsFrom(keysAndValues))
 	}
 }
 
 // Warn writes an unformatted error message to the selected szLog.Logger if
 // warning level messages are enabled.
 func (logger *Logger) Warn(msg ...any) {
-	if logger.IsWarn {
-		logger.output(warnLabel, fmt.Sprint(msg...))
+	if logger.IsWarn() {
+		logger.output(WarnLevel, fmt.Sprint(msg...), nil)
 	}
 }
 
 // Warnf writes a formatted error message to the selected szLog.Logger if
 // warning level messages are enabled.
 func (logger *Logger) Warnf(msgFmt string, msgArgs ...any) {
-	if logger.IsWarn {
-		logger.output(warnLabel, fmt.Sprintf(msgFmt, msgArgs...))
+	if logger.IsWarn() {
+		logger.output(WarnLevel, fmt.Sprintf(msgFmt, msgArgs...), nil)
+	}
+}
+
+// Warnw writes a message with alternating keys and values (or Fields) to the
+// selected szLog.Logger if warning level messages are enabled.
+func (logger *Logger) Warnw(msg string, keysAndValues ...any) {
+	if logger.IsWarn() {
+		logger.output(WarnLevel, msg, fieldsFrom(keysAndValues))
 	}
 }
 
 // Error logs an unformatted error message to the selected szLog.Logger.
 // Error level is always enabled.
 func (logger *Logger) Error(msg ...any) {
-	logger.output(errorLabel, fmt.Sprint(msg...))
+	logger.output(ErrorLevel, fmt.Sprint(msg...), nil)
 }
 
 // Errorf logs an unformatted error message to the selected szLog.Logger.
 // Error level is always enabled.
 func (logger *Logger) Errorf(msgFmt string, msgArgs ...any) {
-	logger.output(errorLabel, fmt.Sprintf(msgFmt, msgArgs...))
+	logger.output(ErrorLevel, fmt.Sprintf(msgFmt, msgArgs...), nil)
+}
+
+// Errorw writes a message with alternating keys and values (or Fields) to the
+// selected szLog.Logger.  Error level is always enabled.
+func (logger *Logger) Errorw(msg string, keysAndValues ...any) {
+	logger.output(ErrorLevel, msg, fieldsFrom(keysAndValues))
+}
+
+// Fatal logs an unformatted fatal message to the selected szLog.Logger,
+// flushes all sinks and then calls the exit function with a code of 1.
+func (logger *Logger) Fatal(msg ...any) {
+	logger.fatal(fmt.Sprint(msg...), nil)
+}
+
+// Fatalf logs a formatted fatal message to the selected szLog.Logger,
+// flushes all sinks and then calls the exit function with a code of 1.
+func (logger *Logger) Fatalf(msgFmt string, msgArgs ...any) {
+	logger.fatal(fmt.Sprintf(msgFmt, msgArgs...), nil)
+}
+
+// Fatalw logs a fatal message with alternating keys and values (or Fields)
+// to the selected szLog.Logger, flushes all sinks and then calls the exit
+// function with a code of 1.
+func (logger *Logger) Fatalw(msg string, keysAndValues ...any) {
+	logger.fatal(msg, fieldsFrom(keysAndValues))
+}
+
+// Panic logs an unformatted panic message to the selected szLog.Logger,
+// flushes all sinks and then panics with the message.
+func (logger *Logger) Panic(msg ...any) {
+	logger.panic(fmt.Sprint(msg...), nil)
+}
+
+// Panicf logs a formatted panic message to the selected szLog.Logger,
+// flushes all sinks and then panics with the message.
+func (logger *Logger) Panicf(msgFmt string, msgArgs ...any) {
+	logger.panic(fmt.Sprintf(msgFmt, msgArgs...), nil)
+}
+
+// Panicw logs a panic message with alternating keys and values (or Fields)
+// to the selected szLog.Logger, flushes all sinks and then panics with the
+// message.
+func (logger *Logger) Panicw(msg string, keysAndValues ...any) {
+	logger.panic(msg, fieldsFrom(keysAndValues))
 }
 
 // Close is a convenience function calling Close() on the provided io.Closer
 // and logging an unformatted error message to the selected szLog.Logger
 // should an error occur.  Good for use in defered close operations.
 func (logger *Logger) Close(closable io.Closer, args ...any) {
-	err := closable.Close()
-
-	if err != nil {
-		msg := ""
-		if len(args) > 0 {
-			msg += " " + fmt.Sprint(args...)
-		}
-		logger.Error("Close", msg, " caused: ", err)
+	msg := ""
+	if len(args) > 0 {
+		msg += " " + fmt.Sprint(args...)
 	}
+	logger.close(closable, msg)
 }
 
 // Closef is a convenience function calling close on the provided io.Closer
@@ -208,14 +731,22 @@ func (logger *Logger) Close(closable io.Closer, args ...any) {
 func (logger *Logger) Closef(
 	closable io.Closer, fmtMsg string, fmtArgs ...any,
 ) {
+	msg := fmt.Sprintf(fmtMsg, fmtArgs...)
+	if len(msg) > 0 {
+		msg = " " + msg
+	}
+	logger.close(closable, msg)
+}
+
+// close calls Close() on the provided io.Closer logging an error message
+// including the provided description should an error occur.
+func (logger *Logger) close(closable io.Closer, msg string) {
 	err := closable.Close()
 
 	if err != nil {
-		msg := fmt.Sprintf(fmtMsg, fmtArgs...)
-		if len(msg) > 0 {
-			msg = " " + msg
-		}
-		logger.Error("Close", msg, " caused: ", err)
+		logger.emit(
+			callerDepth, ErrorLevel, fmt.Sprint("Close", msg, " caused: ", err), nil,
+		)
 	}
 }
 
@@ -224,20 +755,58 @@ func (logger *Logger) Closef(
 // Define the standard szLog.logger object.
 var std *Logger = New(ErrorLevel, log.Default())
 
-// Mirror the std.IsXXXX booleans for symetric access.
-var (
-	IsWarn  bool
-	IsInfo  bool
-	IsDebug bool
-)
+// Default returns the standard szLog.Logger used by the package level
+// functions.
+func Default() *Logger {
+	return std
+}
 
-// SetLevel sets the logging level for the standard szLog.Logger.
+// WithCallerSkip returns a child of the standard szLog.Logger that skips the
+// provided number of additional stack frames when determining the caller of
+// each message.
+func WithCallerSkip(skip int) *Logger {
+	return std.WithCallerSkip(skip)
+}
+
+// With returns a child of the standard szLog.Logger that adds the provided
+// fields (or alternating keys and values) to every message it writes.
+func With(keysAndValues ...any) *Logger {
+	return std.With(keysAndValues...)
+}
+
+// SetLevel sets the logging level for the standard szLog.Logger returning the
+// previous level.  Levels above TraceLevel are ignored.
 func SetLevel(newLevel Level) Level {
-	origLevel := std.SetLevel(newLevel)
-	IsWarn = std.IsWarn
-	IsInfo = std.IsInfo
-	IsDebug = std.IsDebug
-	return origLevel
+	return std.SetLevel(newLevel)
+}
+
+// GetLevel returns the current logging level for the standard szLog.Logger.
+func GetLevel() Level {
+	return std.Level()
+}
+
+// IsWarn mirrors std.IsWarn returning true if warning level messages are
+// enabled on the standard szLog.Logger.
+func IsWarn() bool {
+	return std.IsWarn()
+}
+
+// IsInfo mirrors std.IsInfo returning true if information level messages are
+// enabled on the standard szLog.Logger.
+func IsInfo() bool {
+	return std.IsInfo()
+}
+
+// IsDebug mirrors std.IsDebug returning true if debug level messages are
+// enabled on the standard szLog.Logger.
+func IsDebug() bool {
+	return std.IsDebug()
+}
+
+// IsTrace mirrors std.IsTrace returning true if trace level messages are
+// enabled on the standard szLog.Logger.
+func IsTrace() bool {
+	return std.IsTrace()
 }
 
 // AddWriter wraps the provided io.Writer in a new log.Logger and adds it
@@ -253,76 +822,260 @@ func AddLogger(newLogger *log.Logger) error {
 	return std.AddLogger(newLogger)
 }
 
+// AddJSONWriter adds a JSONSink writing to the provided io.Writer to the
+// logs output by the standard szLog.Logger.
+func AddJSONWriter(newWriter io.Writer) error {
+	return std.AddJSONWriter(newWriter)
+}
+
+// AddSink adds the provided Sink to the logs output by the standard
+// szLog.Logger.  Checks are made and an error is returned should duplicate
+// sinks, szLog.Loggers or underlying io.Writers be added.
+func AddSink(newSink Sink) error {
+	return std.AddSink(newSink)
+}
+
+// AddWriterAt is as AddWriter but the added log only receives messages
+// permitted by its own level rather than the standard szLog.Logger's level.
+func AddWriterAt(
+	level Level, newWriter io.Writer, prefix string, flags int,
+) error {
+	return std.AddWriterAt(level, newWriter, prefix, flags)
+}
+
+// AddLoggerAt is as AddLogger but the added log only receives messages
+// permitted by its own level rather than the standard szLog.Logger's level.
+func AddLoggerAt(level Level, newLogger *log.Logger) error {
+	return std.AddLoggerAt(level, newLogger)
+}
+
+// AddJSONWriterAt is as AddJSONWriter but the added log only receives
+// messages permitted by its own level rather than the standard szLog.Logger's
+// level.
+func AddJSONWriterAt(level Level, newWriter io.Writer) error {
+	return std.AddJSONWriterAt(level, newWriter)
+}
+
+// AddSinkAt is as AddSink but the added sink only receives messages permitted
+// by its own level rather than the standard szLog.Logger's level.  An error is
+// returned if the level is above TraceLevel.
+func AddSinkAt(level Level, newSink Sink) error {
+	return std.AddSinkAt(level, newSink)
+}
+
+// RemoveLogger removes the log.Logger previously added to the standard
+// szLog.Logger returning an error if it is not found.
+func RemoveLogger(oldLogger *log.Logger) error {
+	return std.RemoveLogger(oldLogger)
+}
+
+// RemoveWriter removes all sinks writing to the provided io.Writer from the
+// standard szLog.Logger returning an error if none are found.
+func RemoveWriter(oldWriter io.Writer) error {
+	return std.RemoveWriter(oldWriter)
+}
+
+// RemoveSink removes the sink previously added to the standard szLog.Logger
+// returning an error if it is not found.
+func RemoveSink(oldSink Sink) error {
+	return std.RemoveSink(oldSink)
+}
+
+// ReplaceWriter atomically replaces the sink writing to the old io.Writer
+// with an equivalent sink writing to the new io.Writer on the standard
+// szLog.Logger.
+func ReplaceWriter(oldWriter, newWriter io.Writer) error {
+	return std.ReplaceWriter(oldWriter, newWriter)
+}
+
+// Flush flushes every sink of the standard szLog.Logger implementing Flusher
+// returning the first error encountered.
+func Flush() error {
+	return std.Flush()
+}
+
+// SetExitFunc replaces the function called by Fatal on the standard
+// szLog.Logger, returning the previous function.  A nil function restores
+// os.Exit.
+func SetExitFunc(exitFunc func(code int)) func(code int) {
+	return std.SetExitFunc(exitFunc)
+}
+
+// Sinks returns the sinks currently added to the standard szLog.Logger.
+func Sinks() []Sink {
+	return std.Sinks()
+}
+
+// Trace writes an unformatted information message to the standard
+// szLog.Logger if trace level messages are enabled.
+func Trace(msg ...any) {
+	if std.IsTrace() {
+		std.output(TraceLevel, fmt.Sprint(msg...), nil)
+	}
+}
+
+// Tracef writes a formatted information message to the standard szLog.Logger
+// if trace level messages are enabled.
+func Tracef(msgFmt string, msgArgs ...any) {
+	if std.IsTrace() {
+		std.output(TraceLevel, fmt.Sprintf(msgFmt, msgArgs...), nil)
+	}
+}
+
+// Tracew writes a message with alternating keys and values (or Fields) to the
+// standard szLog.Logger if trace level messages are enabled.
+func Tracew(msg string, keysAndValues ...any) {
+	if std.IsTrace() {
+		std.output(TraceLevel, msg, fieldsFrom(keysAndValues))
+	}
+}
+
 // Debug writes an unformatted information message to the standard
 // szLog.Logger if debug level messages are enabled.
 func Debug(msg ...any) {
-	if IsDebug {
-		std.output(debugLabel, fmt.Sprint(msg...))
+	if std.IsDebug() {
+		std.output(DebugLevel, fmt.Sprint(msg...), nil)
 	}
 }
 
 // Debugf writes a formatted information message to the standard szLog.Logger
 // if debug level messages are enabled.
 func Debugf(msgFmt string, msgArgs ...any) {
-	if IsDebug {
-		std.output(debugLabel, fmt.Sprintf(msgFmt, msgArgs...))
+	if std.IsDebug() {
+		std.output(DebugLevel, fmt.Sprintf(msgFmt, msgArgs...), nil)
+	}
+}
+
+// Debugw writes a message with alternating keys and values (or Fields) to the
+// standard szLog.Logger if debug level messages are enabled.
+func Debugw(msg string, keysAndValues ...any) {
+	if std.IsDebug() {
+		std.output(DebugLevel, msg, fieldsFrom(keysAndValues))
 	}
 }
 
 // Info writes an unformatted information message to the standard szLog.Logger
 // if iformation level messages are enabled.
 func Info(msg ...any) {
-	if IsInfo {
-		std.output(infoLabel, fmt.Sprint(msg...))
+	if std.IsInfo() {
+		std.output(InfoLevel, fmt.Sprint(msg...), nil)
 	}
 }
 
 // Infof writes a formatted information message to the standard szLog.Logger
 // if warning level messages are enabled.
 func Infof(msgFmt string, msgArgs ...any) {
-	if IsInfo {
-		std.output(infoLabel, fmt.Sprintf(msgFmt, msgArgs...))
+	if std.IsInfo() {
+		std.output(InfoLevel, fmt.Sprintf(msgFmt, msgArgs...), nil)
+	}
+}
+
+// Infow writes a message with alternating keys and values (or Fields) to the
+// standard szLog.Logger if information level messages are enabled.
+func Infow(msg string, keysAndValues ...any) {
+	if std.IsInfo() {
+		std.output(InfoLevel, msg, fieldsFrom(keysAndValues))
 	}
 }
 
 // Warn writes an unformatted warning message to the standard szLog.Logger if
 // warning level messages are enabled.
 func Warn(msg ...any) {
-	if IsWarn {
-		std.output(warnLabel, fmt.Sprint(msg...))
+	if std.IsWarn() {
+		std.output(WarnLevel, fmt.Sprint(msg...), nil)
 	}
 }
 
 // Warnf writes a formatted warning message to the standard szLog.Logger if
 // warning level messages are enabled.
 func Warnf(msgFmt string, msgArgs ...any) {
-	if IsWarn {
-		std.output(warnLabel, fmt.Sprintf(msgFmt, msgArgs...))
+	if std.IsWarn() {
+		std.output(WarnLevel, fmt.Sprintf(msgFmt, msgArgs...), nil)
+	}
+}
+
+// Warnw writes a message with alternating keys and values (or Fields) to the
+// standard szLog.Logger if warning level messages are enabled.
+func Warnw(msg string, keysAndValues ...any) {
+	if std.IsWarn() {
+		std.output(WarnLevel, msg, fieldsFrom(keysAndValues))
 	}
 }
 
 // Error writes an unformatted error message to the standard szLog.Logger.
 // Error level is always enabled.
 func Error(msg ...any) {
-	std.output(errorLabel, fmt.Sprint(msg...))
+	std.output(ErrorLevel, fmt.Sprint(msg...), nil)
 }
 
 // Errorf writes a formatted error message to the standard szLog.Logger.
 // Error level is always enabled.
 func Errorf(msgFmt string, msgArgs ...any) {
-	std.output(errorLabel, fmt.Sprintf(msgFmt, msgArgs...))
+	std.output(ErrorLevel, fmt.Sprintf(msgFmt, msgArgs...), nil)
+}
+
+// Errorw writes a message with alternating keys and values (or Fields) to the
+// standard szLog.Logger.  Error level is always enabled.
+func Errorw(msg string, keysAndValues ...any) {
+	std.output(ErrorLevel, msg, fieldsFrom(keysAndValues))
+}
+
+// Fatal logs an unformatted fatal message to the standard szLog.Logger,
+// flushes all sinks and then calls the exit function with a code of 1.
+func Fatal(msg ...any) {
+	std.fatal(fmt.Sprint(msg...), nil)
+}
+
+// Fatalf logs a formatted fatal message to the standard szLog.Logger,
+// flushes all sinks and then calls the exit function with a code of 1.
+func Fatalf(msgFmt string, msgArgs ...any) {
+	std.fatal(fmt.Sprintf(msgFmt, msgArgs...), nil)
+}
+
+// Fatalw logs a fatal message with alternating keys and values (or Fields)
+// to the standard szLog.Logger, flushes all sinks and then calls the exit
+// function with a code of 1.
+func Fatalw(msg string, keysAndValues ...any) {
+	std.fatal(msg, fieldsFrom(keysAndValues))
+}
+
+// Panic logs an unformatted panic message to the standard szLog.Logger,
+// flushes all sinks and then panics with the message.
+func Panic(msg ...any) {
+	std.panic(fmt.Sprint(msg...), nil)
+}
+
+// Panicf logs a formatted panic message to the standard szLog.Logger,
+// flushes all sinks and then panics with the message.
+func Panicf(msgFmt string, msgArgs ...any) {
+	std.panic(fmt.Sprintf(msgFmt, msgArgs...), nil)
+}
+
+// Panicw logs a panic message with alternating keys and values (or Fields)
+// to the standard szLog.Logger, flushes all sinks and then panics with the
+// message.
+func Panicw(msg string, keysAndValues ...any) {
+	std.panic(msg, fieldsFrom(keysAndValues))
 }
 
 // Close is a convenience function calling Close() on the provided io.Closer
 // and logging an unformatted error message to the standard logger should an
 // error occur.  Good for use in defered close operations.
 func Close(closable io.Closer, args ...any) {
-	std.Close(closable, args...)
+	msg := ""
+	if len(args) > 0 {
+		msg += " " + fmt.Sprint(args...)
+	}
+	std.close(closable, msg)
 }
 
 // Closef is a convenience function calling close on the provided io.Closer
 // and logging a formatted error message to the standard logger should an
 // error occur.  Good for use in defered close operations.
 func Closef(closable io.Closer, fmtMsg string, fmtArgs ...any) {
-	std.Closef(closable, fmtMsg, fmtArgs...)
+	msg := fmt.Sprintf(fmtMsg, fmtArgs...)
+	if len(msg) > 0 {
+		msg = " " + msg
+	}
+	std.close(closable, msg)
 }
@@ -20,10 +20,17 @@
 package szLog
 
 import (
+	"bytes"
+	"errors"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/dancsecs/szTest"
@@ -57,49 +64,49 @@ func runDefaultLogTest() {
 
 func runDefaultLogTestIf() {
 	SetLevel(ErrorLevel)
-	if IsDebug {
+	if IsDebug() {
 		Debug("1-", "WE SHOULD ", "NOT SEE", " THIS DEBUG MESSAGE")
 	}
-	if IsInfo {
+	if IsInfo() {
 		Info("1-", "WE SHOULD ", "NOT SEE", " THIS INFO MESSAGE")
 	}
-	if IsWarn {
+	if IsWarn() {
 		Warn("1-", "WE SHOULD ", "NOT SEE", " THIS WARNING MESSAGE")
 	}
 	Error("1-", "WE SHOULD ", "SEE", " THIS ERROR MESSAGE")
 
 	SetLevel(WarnLevel)
-	if IsDebug {
+	if IsDebug() {
 		Debug("2-", "WE SHOULD ", "NOT SEE", " THIS DEBUG MESSAGE")
 	}
-	if IsInfo {
+	if IsInfo() {
 		Info("2-", "WE SHOULD ", "NOT SEE", " THIS INFO MESSAGE")
 	}
-	if IsWarn {
+	if IsWarn() {
 		Warn("2-", "WE SHOULD ", "SEE", " THIS WARNING MESSAGE")
 	}
 	Error("2-", "WE SHOULD ", "SEE", " THIS ERROR MESSAGE")
 
 	SetLevel(InfoLevel)
-	if IsDebug {
+	if IsDebug() {
 		Debug("3-", "WE SHOULD ", "NOT SEE", " THIS DEBUG MESSAGE")
 	}
-	if IsInfo {
+	if IsInfo() {
 		Info("3-", "WE SHOULD ", "SEE", " THIS INFO MESSAGE")
 	}
-	if IsWarn {
+	if IsWarn() {
 		Warn("3-", "WE SHOULD ", "SEE", " THIS WARNING MESSAGE")
 	}
 	Error("3-", "WE SHOULD ", "SEE", " THIS ERROR MESSAGE")
 
 	SetLevel(DebugLevel)
-	if IsDebug {
+	if IsDebug() {
 		Debug("4-", "WE SHOULD ", "SEE", " THIS DEBUG MESSAGE")
 	}
-	if IsInfo {
+	if IsInfo() {
 		Info("4-", "WE SHOULD ", "SEE", " THIS INFO MESSAGE")
 	}
-	if IsWarn {
+	if IsWarn() {
 		Warn("4-", "WE SHOULD ", "SEE", " THIS WARNING MESSAGE")
 	}
 	Error("4-", "WE SHOULD ", "SEE", " THIS ERROR MESSAGE")
@@ -156,49 +163,49 @@ func runLogTest(logger *Logger) {
 
 func runLogTestIf(logger *Logger) {
 	logger.SetLevel(ErrorLevel)
-	if logger.IsDebug {
+	if logger.IsDebug() {
 		logger.Debug("1-", "WE SHOULD ", "NOT SEE", " THIS DEBUG MESSAGE")
 	}
-	if logger.IsInfo {
+	if logger.IsInfo() {
 		logger.Info("1-", "WE SHOULD ", "NOT SEE", " THIS INFO MESSAGE")
 	}
-	if logger.IsWarn {
+	if logger.IsWarn() {
 		logger.Warn("1-", "WE SHOULD ", "NOT SEE", " THIS WARNING MESSAGE")
 	}
 	logger.Error("1-", "WE SHOULD ", "SEE", " THIS ERROR MESSAGE")
 
 	logger.SetLevel(WarnLevel)
-	if logger.IsDebug {
+	if logger.IsDebug() {
 		logger.Debug("2-", "WE SHOULD ", "NOT SEE", " THIS DEBUG MESSAGE")
 	}
-	if logger.IsInfo {
+	if logger.IsInfo() {
 		logger.Info("2-", "WE SHOULD ", "NOT SEE", " THIS INFO MESSAGE")
 	}
-	if logger.IsWarn {
+	if logger.IsWarn() {
 		logger.Warn("2-", "WE SHOULD ", "SEE", " THIS WARNING MESSAGE")
 	}
 	logger.Error("2-", "WE SHOULD ", "SEE", " THIS ERROR MESSAGE")
 
 	logger.SetLevel(InfoLevel)
-	if logger.IsDebug {
+	if logger.IsDebug() {
 		logger.Debug("3-", "WE SHOULD ", "NOT SEE", " THIS DEBUG MESSAGE")
 	}
-	if logger.IsInfo {
+	if logger.IsInfo() {
 		logger.Info("3-", "WE SHOULD ", "SEE", " THIS INFO MESSAGE")
 	}
-	if logger.IsWarn {
+	if logger.IsWarn() {
 		logger.Warn("3-", "WE SHOULD ", "SEE", " THIS WARNING MESSAGE")
 	}
 	logger.Error("3-", "WE SHOULD ", "SEE", " THIS ERROR MESSAGE")
 
 	logger.SetLevel(DebugLevel)
-	if logger.IsDebug {
+	if logger.IsDebug() {
 		logger.Debug("4-", "WE SHOULD ", "SEE", " THIS DEBUG MESSAGE")
 	}
-	if logger.IsInfo {
+	if logger.IsInfo() {
 		logger.Info("4-", "WE SHOULD ", "SEE", " THIS INFO MESSAGE")
 	}
-	if logger.IsWarn {
+	if logger.IsWarn() {
 		logger.Warn("4-", "WE SHOULD ", "SEE", " THIS WARNING MESSAGE")
 	}
 	logger.Error("4-", "WE SHOULD ", "SEE", " THIS ERROR MESSAGE")
@@ -417,3 +424,643 @@ func TestSzLog_CloseFile(t *testing.T) {
 		`E: Close msg1 msg2 caused: close {{tstFile}}: file already closed` + "\n" +
 		"")
 }
+
+func Test_SzLog_ConcurrentSetLevelAndAddLogger(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	const iterations = 200
+
+	logger := New(ErrorLevel, log.New(io.Discard, "", 0))
+	buffers := make([]*bytes.Buffer, iterations)
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			logger.SetLevel(Level(i % 4))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			buffers[i] = new(bytes.Buffer)
+			_ = logger.AddWriter(buffers[i], "", 0)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			if logger.IsDebug() {
+				logger.Debug("debug ", i)
+			}
+			logger.Infof("info %d", i)
+			logger.Warn("warn ", i)
+			logger.Error("error ", i)
+		}
+	}()
+	wg.Wait()
+
+	chk.Int(len(logger.core().sinks()), iterations+1)
+
+	logger.SetLevel(WarnLevel)
+	logger.Warn("last")
+	for _, b := range buffers {
+		chk.True(bytes.HasSuffix(b.Bytes(), []byte("W: last\n")))
+	}
+}
+
+func Test_SzLog_Default_ConcurrentSetLevel(t *testing.T) {
+	chk := szTest.CaptureLog(t)
+	defer chk.Release()
+
+	const iterations = 200
+
+	SetLevel(ErrorLevel)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			SetLevel(Level(i % 3)) // Never enables debug.
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			_ = IsWarn()
+			_ = IsInfo()
+			_ = IsDebug()
+			Debug("message")
+		}
+	}()
+	wg.Wait()
+
+	SetLevel(InfoLevel)
+	chk.Int(int(GetLevel()), int(InfoLevel))
+	chk.True(IsWarn())
+	chk.True(IsInfo())
+	chk.False(IsDebug())
+
+	Error("after")
+
+	chk.Log("E: after\n")
+}
+
+func Test_SzLog_Fields(t *testing.T) {
+	chk := szTest.CaptureLog(t)
+	defer chk.Release()
+
+	logger := New(InfoLevel, log.Default())
+
+	logger.Debugw("not seen", "k", 1)
+	logger.Infow("user login", "user", "bob", "ms", 12)
+	logger.Warnw("warning", String("reason", "slow disk"))
+	logger.Errorw("line 1\nline 2", "count", 2)
+
+	SetLevel(WarnLevel)
+	Debugw("not seen", "k", 1)
+	Infow("not seen", "k", 1)
+	Warnw("std warning", Int("n", 7))
+	Errorw("std error")
+
+	chk.Log("" +
+		"I: user login user=bob ms=12\n" +
+		`W: warning reason="slow disk"` + "\n" +
+		"E: line 1\n" +
+		"+  line 2 count=2\n" +
+		"W: std warning n=7\n" +
+		"E: std error\n" +
+		"")
+}
+
+func Test_SzLog_With(t *testing.T) {
+	chk := szTest.CaptureLog(t)
+	defer chk.Release()
+
+	parent := New(InfoLevel, log.Default())
+	request := parent.With("request", "r-1", String("tenant", "acme"))
+	sub := request.With("step", 2)
+
+	parent.Info("parent")
+	request.Info("request")
+	request.Infow("with fields", "user", "bob")
+	sub.Error("sub")
+	sub.Debug("not seen")
+
+	parent.SetLevel(DebugLevel)
+	sub.Debug("seen")
+
+	chk.Int(len(parent.fields), 0)
+	chk.Int(len(request.fields), 2)
+
+	SetLevel(WarnLevel)
+	With("pkg", "std").Warn("std child")
+
+	chk.Log("" +
+		"I: parent\n" +
+		"I: request request=r-1 tenant=acme\n" +
+		"I: with fields request=r-1 tenant=acme user=bob\n" +
+		"E: sub request=r-1 tenant=acme step=2\n" +
+		"D: seen request=r-1 tenant=acme step=2\n" +
+		"W: std child pkg=std\n" +
+		"")
+}
+
+func Test_SzLog_WithSharesLogs(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	parentBuf := new(bytes.Buffer)
+	childBuf := new(bytes.Buffer)
+
+	parent := New(ErrorLevel, log.New(parentBuf, "", 0))
+	child := parent.With("id", 1)
+
+	chk.NoErr(child.AddWriter(childBuf, "", 0))
+	chk.Err(
+		parent.AddWriter(childBuf, "", 0),
+		"duplicate os.Writer added",
+	)
+
+	parent.Error("from parent")
+	child.Error("from child")
+
+	chk.Str(parentBuf.String(), "E: from parent\nE: from child id=1\n")
+	chk.Str(childBuf.String(), "E: from parent\nE: from child id=1\n")
+}
+
+func Test_SzLog_PerSinkLevel(t *testing.T) {
+	chk := szTest.CaptureLog(t)
+	defer chk.Release()
+
+	debugBuf := new(bytes.Buffer)
+	jsonBuf := new(bytes.Buffer)
+
+	logger := New(WarnLevel, log.Default())
+	chk.False(logger.IsInfo())
+
+	chk.NoErr(logger.AddWriterAt(DebugLevel, debugBuf, "", 0))
+	chk.NoErr(logger.AddJSONWriterAt(ErrorLevel, jsonBuf))
+	chk.Err(
+		logger.AddLoggerAt(InfoLevel, log.New(debugBuf, "", 0)),
+		"duplicate os.Writer added",
+	)
+
+	chk.Int(int(logger.Level()), int(WarnLevel))
+	chk.True(logger.IsDebug())
+	chk.True(logger.IsInfo())
+	chk.True(logger.IsWarn())
+
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+
+	logger.SetLevel(InfoLevel)
+	logger.Info("info 2")
+
+	chk.Str(debugBuf.String(), ""+
+		"D: debug\n"+
+		"I: info\n"+
+		"W: warn\n"+
+		"E: error\n"+
+		"I: info 2\n",
+	)
+	chk.Str(
+		normJSON(jsonBuf.String()),
+		`{"time":"T","level":"error","msg":"error"}`+"\n",
+	)
+	chk.Log("" +
+		"W: warn\n" +
+		"E: error\n" +
+		"I: info 2\n" +
+		"")
+}
+
+func Test_SzLog_Default_PerSinkLevel(t *testing.T) {
+	chk := szTest.CaptureLog(t)
+	defer chk.Release()
+
+	SetLevel(ErrorLevel)
+
+	infoBuf := new(bytes.Buffer)
+	infoLogger := log.New(infoBuf, "", 0)
+	jsonSink := NewJSONSink(new(bytes.Buffer))
+	jsonBuf := new(bytes.Buffer)
+	errBuf := new(bytes.Buffer)
+
+	chk.NoErr(AddLoggerAt(InfoLevel, infoLogger))
+	chk.NoErr(AddSinkAt(ErrorLevel, jsonSink))
+	chk.NoErr(AddJSONWriterAt(ErrorLevel, jsonBuf))
+	chk.NoErr(AddWriterAt(ErrorLevel, errBuf, "", 0))
+	defer func() {
+		chk.NoErr(RemoveLogger(infoLogger))
+		chk.NoErr(RemoveSink(jsonSink))
+		chk.NoErr(RemoveWriter(jsonBuf))
+		chk.NoErr(RemoveWriter(errBuf))
+		chk.False(IsInfo())
+	}()
+
+	chk.True(IsInfo())
+	chk.False(IsDebug())
+
+	Debug("debug")
+	Info("info")
+	Error("error")
+
+	chk.Str(infoBuf.String(), "I: info\nE: error\n")
+	chk.Log("E: error\n")
+}
+
+func Test_SzLog_RemoveAndReplace(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf1 := new(bytes.Buffer)
+	buf2 := new(bytes.Buffer)
+	buf3 := new(bytes.Buffer)
+	jsonBuf := new(bytes.Buffer)
+	logLogger := log.New(buf1, "pre:", log.Lmsgprefix)
+	custom := &testSink{}
+
+	logger := New(ErrorLevel, logLogger)
+	chk.NoErr(logger.AddWriterAt(DebugLevel, buf2, "", 0))
+	chk.NoErr(logger.AddJSONWriter(jsonBuf))
+	chk.NoErr(logger.AddSink(custom))
+	chk.Int(len(logger.Sinks()), 4)
+	chk.True(logger.IsDebug())
+
+	chk.Err(logger.RemoveLogger(log.New(buf1, "", 0)), "logger not found")
+	chk.Err(logger.RemoveWriter(buf3), "writer not found")
+	chk.Err(logger.RemoveSink(&testSink{}), "sink not found")
+
+	chk.Err(logger.ReplaceWriter(buf3, buf1), "writer not found")
+	chk.Err(logger.ReplaceWriter(buf1, buf2), "duplicate os.Writer added")
+	chk.NoErr(logger.ReplaceWriter(buf1, buf3))
+	chk.NoErr(logger.ReplaceWriter(jsonBuf, buf1))
+
+	logger.Debug("debug")
+	logger.Error("error")
+
+	chk.Str(buf2.String(), "D: debug\nE: error\n")
+	chk.Str(buf3.String(), "pre:E: error\n")
+	chk.Str(
+		normJSON(buf1.String()),
+		`{"time":"T","level":"error","msg":"error"}`+"\n",
+	)
+	chk.Str(jsonBuf.String(), "")
+	chk.Int(custom.count, 1)
+
+	chk.NoErr(logger.RemoveWriter(buf2))
+	chk.False(logger.IsDebug())
+	chk.NoErr(logger.RemoveSink(custom))
+	chk.NoErr(logger.RemoveWriter(buf1))
+	chk.Int(len(logger.Sinks()), 1)
+	_, isText := logger.Sinks()[0].(*TextSink)
+	chk.True(isText)
+	chk.NoErr(logger.RemoveWriter(buf3))
+	chk.Int(len(logger.Sinks()), 0)
+
+	logger.Error("nowhere")
+}
+
+func Test_SzLog_Default_ReplaceWriter(t *testing.T) {
+	chk := szTest.CaptureLog(t)
+	defer chk.Release()
+
+	buf1 := new(bytes.Buffer)
+	buf2 := new(bytes.Buffer)
+
+	chk.NoErr(AddWriter(buf1, "", 0))
+	chk.Int(len(Sinks()), len(std.core().sinks()))
+	chk.NoErr(ReplaceWriter(buf1, buf2))
+	Error("replaced")
+	chk.NoErr(RemoveWriter(buf2))
+	Error("removed")
+
+	chk.Str(buf1.String(), "")
+	chk.Str(buf2.String(), "E: replaced\n")
+	chk.Log("E: replaced\nE: removed\n")
+}
+
+func Test_SzLog_FatalAndPanic(t *testing.T) {
+	chk := szTest.CaptureLog(t)
+	defer chk.Release()
+
+	flushBuf := new(flushBuffer)
+	jsonBuf := new(bytes.Buffer)
+
+	logger := New(ErrorLevel, log.Default())
+	chk.NoErr(logger.AddWriter(flushBuf, "", 0))
+	chk.NoErr(logger.AddJSONWriterAt(WarnLevel, jsonBuf))
+
+	exitCodes := []int{}
+	lastFunc := logger.SetExitFunc(func(code int) {
+		exitCodes = append(exitCodes, code)
+	})
+	chk.True(lastFunc != nil)
+
+	logger.Fatal("fatal ", 1)
+	logger.Fatalf("fatal %d", 2)
+	logger.Fatalw("fatal 3", "k", "v")
+	chk.Int(len(exitCodes), 3)
+	chk.Int(exitCodes[0], 1)
+	chk.Int(flushBuf.flushes, 3)
+
+	chk.Panic(func() { logger.Panic("panic ", 1) }, "panic 1")
+	chk.Panic(func() { logger.Panicf("panic %d", 2) }, "panic 2")
+	chk.Panic(func() { logger.Panicw("panic 3", "k", "v") }, "panic 3")
+	chk.Int(flushBuf.flushes, 6)
+
+	chk.True(logger.SetExitFunc(nil) != nil)
+
+	chk.Str(flushBuf.String(), ""+
+		"F: fatal 1\n"+
+		"F: fatal 2\n"+
+		"F: fatal 3 k=v\n"+
+		"P: panic 1\n"+
+		"P: panic 2\n"+
+		"P: panic 3 k=v\n",
+	)
+	chk.Str(
+		normJSON(jsonBuf.String()), ""+
+			`{"time":"T","level":"fatal","msg":"fatal 1"}`+"\n"+
+			`{"time":"T","level":"fatal","msg":"fatal 2"}`+"\n"+
+			`{"time":"T","level":"fatal","msg":"fatal 3",`+
+			`"fields":{"k":"v"}}`+"\n"+
+			`{"time":"T","level":"panic","msg":"panic 1"}`+"\n"+
+			`{"time":"T","level":"panic","msg":"panic 2"}`+"\n"+
+			`{"time":"T","level":"panic","msg":"panic 3",`+
+			`"fields":{"k":"v"}}`+"\n",
+	)
+	chk.Log("" +
+		"F: fatal 1\n" +
+		"F: fatal 2\n" +
+		"F: fatal 3 k=v\n" +
+		"P: panic 1\n" +
+		"P: panic 2\n" +
+		"P: panic 3 k=v\n" +
+		"")
+}
+
+func Test_SzLog_ZeroValue(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	new(Logger).Error("no sinks")
+
+	var logger Logger
+	buf := new(bytes.Buffer)
+	chk.Int(int(logger.Level()), int(ErrorLevel))
+	chk.NoErr(logger.AddWriter(buf, "", 0))
+	logger.Error("error")
+	logger.Warn("filtered")
+	logger.SetLevel(InfoLevel)
+	logger.With("k", "v").Info("child")
+
+	chk.Str(buf.String(), ""+
+		"E: error\n"+
+		"I: child k=v\n",
+	)
+}
+
+func Test_SzLog_InvalidLevel(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	logger := New(InfoLevel, log.New(buf, "", 0))
+
+	chk.Int(int(logger.SetLevel(FatalLevel)), int(InfoLevel))
+	chk.Int(int(logger.SetLevel(PanicLevel)), int(InfoLevel))
+	chk.Int(int(logger.SetLevel(TraceLevel+1)), int(InfoLevel))
+	chk.Int(int(logger.Level()), int(InfoLevel))
+	chk.False(logger.Enabled(TraceLevel))
+
+	chk.Err(
+		logger.AddSinkAt(FatalLevel, NewJSONSink(new(bytes.Buffer))),
+		"invalid level: fatal",
+	)
+	chk.Err(
+		logger.AddWriterAt(TraceLevel+1, new(bytes.Buffer), "", 0),
+		"invalid level: level(5)",
+	)
+	chk.Int(len(logger.Sinks()), 1)
+	chk.False(logger.Enabled(TraceLevel))
+
+	logger.Debug("filtered")
+	logger.Info("logged")
+	chk.Str(buf.String(), "I: logged\n")
+}
+
+func Test_SzLog_Default_FatalAndPanic(t *testing.T) {
+	chk := szTest.CaptureLog(t)
+	defer chk.Release()
+
+	exitCode := 0
+	lastFunc := SetExitFunc(func(code int) {
+		exitCode += code
+	})
+	defer SetExitFunc(lastFunc)
+
+	Fatal("fatal ", 1)
+	Fatalf("fatal %d", 2)
+	Fatalw("fatal 3", "k", "v")
+	chk.Int(exitCode, 3)
+	chk.NoErr(Flush())
+
+	chk.Panic(func() { Panic("panic ", 1) }, "panic 1")
+	chk.Panic(func() { Panicf("panic %d", 2) }, "panic 2")
+	chk.Panic(func() { Panicw("panic 3", "k", "v") }, "panic 3")
+
+	chk.Log("" +
+		"F: fatal 1\n" +
+		"F: fatal 2\n" +
+		"F: fatal 3 k=v\n" +
+		"P: panic 1\n" +
+		"P: panic 2\n" +
+		"P: panic 3 k=v\n" +
+		"")
+}
+
+func Test_SzLog_Trace(t *testing.T) {
+	chk := szTest.CaptureLog(t)
+	defer chk.Release()
+
+	chk.Int(int(TraceLevel), int(DebugLevel)+1)
+
+	logger := New(DebugLevel, log.Default())
+	chk.False(logger.IsTrace())
+	logger.Trace("not seen")
+	logger.Tracef("not %s", "seen")
+	logger.Tracew("not seen", "k", 1)
+
+	logger.SetLevel(TraceLevel)
+	chk.True(logger.IsTrace())
+	logger.Trace("trace ", 1)
+	logger.Tracef("trace %d", 2)
+	logger.Tracew("trace 3", "k", 3)
+	logger.Debug("debug")
+
+	SetLevel(DebugLevel)
+	chk.False(IsTrace())
+	Trace("not seen")
+	Tracef("not %s", "seen")
+	Tracew("not seen", "k", 1)
+
+	SetLevel(TraceLevel)
+	chk.True(IsTrace())
+	Trace("std trace ", 1)
+	Tracef("std trace %d", 2)
+	Tracew("std trace 3", "k", 3)
+	SetLevel(ErrorLevel)
+
+	chk.Log("" +
+		"T: trace 1\n" +
+		"T: trace 2\n" +
+		"T: trace 3 k=3\n" +
+		"D: debug\n" +
+		"T: std trace 1\n" +
+		"T: std trace 2\n" +
+		"T: std trace 3 k=3\n" +
+		"")
+}
+
+// nextLine returns the line number following that of its caller.
+func nextLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line + 1
+}
+
+func Test_SzLog_Caller(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	rec := new(recordSink)
+	logger := New(TraceLevel, log.New(io.Discard, "", 0))
+	chk.NoErr(logger.AddSink(rec))
+	logger.SetExitFunc(func(int) {})
+
+	want := []int{}
+	want = append(want, nextLine())
+	logger.Trace("trace")
+	want = append(want, nextLine())
+	logger.Debugf("debug")
+	want = append(want, nextLine())
+	logger.Infow("info")
+	want = append(want, nextLine())
+	logger.Warn("warn")
+	want = append(want, nextLine())
+	logger.Error("error")
+	want = append(want, nextLine())
+	logger.With("k", "v").Errorf("error")
+	want = append(want, nextLine())
+	logger.Fatal("fatal")
+	want = append(want, nextLine())
+	chk.Panic(func() { logger.Panic("panic") }, "panic")
+	want = append(want, nextLine())
+	logger.Close(alreadyClosed{})
+	want = append(want, nextLine())
+	logger.Closef(alreadyClosed{}, "msg")
+
+	chk.Int(len(rec.entries), len(want))
+	for i, e := range rec.entries {
+		chk.Str(e.Caller.File[strings.LastIndexByte(e.Caller.File, '/')+1:],
+			"szLog_test.go",
+		)
+		chk.Int(e.Caller.Line, want[i], strconv.Itoa(i))
+	}
+}
+
+func Test_SzLog_Default_Caller(t *testing.T) {
+	chk := szTest.CaptureLog(t)
+	defer chk.Release()
+
+	SetLevel(TraceLevel)
+	defer SetLevel(ErrorLevel)
+
+	rec := new(recordSink)
+	chk.NoErr(AddSink(rec))
+	defer func() { chk.NoErr(RemoveSink(rec)) }()
+
+	lastExit := SetExitFunc(func(int) {})
+	defer SetExitFunc(lastExit)
+
+	want := []int{}
+	want = append(want, nextLine())
+	Tracef("trace")
+	want = append(want, nextLine())
+	Debugw("debug")
+	want = append(want, nextLine())
+	Info("info")
+	want = append(want, nextLine())
+	Warnf("warn")
+	want = append(want, nextLine())
+	Errorw("error")
+	want = append(want, nextLine())
+	Fatalf("fatal")
+	want = append(want, nextLine())
+	Close(alreadyClosed{})
+	want = append(want, nextLine())
+	Closef(alreadyClosed{}, "msg")
+
+	chk.Int(len(rec.entries), len(want))
+	for i, e := range rec.entries {
+		chk.Int(e.Caller.Line, want[i], strconv.Itoa(i))
+	}
+
+	chk.Log("" +
+		"T: trace\n" +
+		"D: debug\n" +
+		"I: info\n" +
+		"W: warn\n" +
+		"E: error\n" +
+		"F: fatal\n" +
+		"E: Close caused: already closed\n" +
+		"E: Close msg caused: already closed\n" +
+		"")
+}
+
+// alreadyClosed is an io.Closer that always fails.
+type alreadyClosed struct{}
+
+func (alreadyClosed) Close() error {
+	return errors.New("already closed")
+}
+
+// logHelper wraps a szLog.Logger as an application helper might.
+func logHelper(logger *Logger, msg string) {
+	logger.WithCallerSkip(1).Error(msg)
+}
+
+func Test_SzLog_CallerSkipAndFileFlags(t *testing.T) {
+	chk := szTest.CaptureNothing(t)
+	defer chk.Release()
+
+	buf := new(bytes.Buffer)
+	logger := New(ErrorLevel, log.New(buf, "", log.Lshortfile))
+
+	line1 := nextLine()
+	logger.Error("direct")
+	line2 := nextLine()
+	logHelper(logger, "helper")
+	line3 := nextLine()
+	logHelper(logger.With("k", 1), "bound")
+
+	chk.Int(logger.WithCallerSkip(2).WithCallerSkip(1).callerSkip, 3)
+
+	// Callers on the stack are reported by the added log.Logger itself.
+	sink, _ := logger.Sinks()[0].(*TextSink)
+	chk.True(sink.located == nil)
+
+	chk.Str(buf.String(), ""+
+		"szLog_test.go:"+strconv.Itoa(line1)+": E: direct\n"+
+		"szLog_test.go:"+strconv.Itoa(line2)+": E: helper\n"+
+		"szLog_test.go:"+strconv.Itoa(line3)+": E: bound k=1\n",
+	)
+
+	chk.Int(WithCallerSkip(1).callerSkip, 1)
+}